@@ -0,0 +1,254 @@
+// Package admin provides an HTTP API to inspect the flags of a running
+// SimpleEnvProvider and override them temporarily, e.g. to flip a kill switch
+// during an incident without changing the environment and restarting.
+//
+// The handler does no authentication: mount it on an internal listener or behind
+// middleware that does, under a prefix stripped with http.StripPrefix:
+//
+//	mux.Handle("/admin/", http.StripPrefix("/admin", requireOnCall(admin.NewHandler(p))))
+//
+// It serves:
+//
+//	GET    /flags                  list every flag
+//	GET    /flags/{key}            show one flag
+//	PUT    /flags/{key}/override   override a flag: {"value": true, "ttl": "30m"}
+//	DELETE /flags/{key}/override   clear the override of a flag
+//	DELETE /overrides              clear every override
+//
+// An overridden flag is listed with the value the source holds for it under
+// "source". Values read from a file named by a _FILE variable are secrets: only
+// the variable is shown, under "file", and never the value.
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+const (
+	// DefaultTTL is how long an override lasts when the request does not say.
+	DefaultTTL = time.Hour
+	// DefaultMaxTTL is the longest override the handler accepts.
+	DefaultMaxTTL = 24 * time.Hour
+)
+
+// Handler serves the admin API for a SimpleEnvProvider.
+type Handler struct {
+	provider   *provider.SimpleEnvProvider
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	mux        *http.ServeMux
+}
+
+type HandlerOption func(*Handler)
+
+func NewHandler(p *provider.SimpleEnvProvider, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		provider:   p,
+		defaultTTL: DefaultTTL,
+		maxTTL:     DefaultMaxTTL,
+		mux:        http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("GET /flags", h.listFlags)
+	h.mux.HandleFunc("GET /flags/{key}", h.getFlag)
+	h.mux.HandleFunc("PUT /flags/{key}/override", h.setOverride)
+	h.mux.HandleFunc("DELETE /flags/{key}/override", h.clearOverride)
+	h.mux.HandleFunc("DELETE /overrides", h.clearOverrides)
+	return h
+}
+
+// WithDefaultTTL sets how long an override lasts when the request does not say.
+func WithDefaultTTL(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.defaultTTL = d
+	}
+}
+
+// WithMaxTTL sets the longest override the handler accepts, so that a forgotten
+// override cannot outlive an incident by much.
+func WithMaxTTL(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.maxTTL = d
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type flagResponse struct {
+	Key      string            `json:"key"`
+	Raw      string            `json:"raw"`
+	Type     string            `json:"type,omitempty"`
+	Value    interface{}       `json:"value,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	File     string            `json:"file,omitempty"`
+	Override *overrideResponse `json:"override,omitempty"`
+	Source   *flagResponse     `json:"source,omitempty"`
+}
+
+type overrideResponse struct {
+	Value     string     `json:"value"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type overrideRequest struct {
+	// Value is either a JSON string, used as the raw value, or any other JSON value,
+	// whose encoding is used as the raw value.
+	Value json.RawMessage `json:"value"`
+	TTL   string          `json:"ttl"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) listFlags(w http.ResponseWriter, r *http.Request) {
+	resp := struct {
+		Flags []flagResponse `json:"flags"`
+	}{Flags: []flagResponse{}}
+	for _, f := range h.provider.Flags() {
+		resp.Flags = append(resp.Flags, toFlagResponse(f))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getFlag(w http.ResponseWriter, r *http.Request) {
+	f, ok := h.flag(r.PathValue("key"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("flag %s not found", r.PathValue("key"))})
+		return
+	}
+	writeJSON(w, http.StatusOK, toFlagResponse(f))
+}
+
+func (h *Handler) setOverride(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+
+	var req overrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+	value, err := rawValue(req.Value)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	ttl, err := h.ttl(req.TTL)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.provider.SetOverride(key, value, ttl); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	f, _ := h.flag(key)
+	writeJSON(w, http.StatusOK, toFlagResponse(f))
+}
+
+func (h *Handler) clearOverride(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !h.provider.ClearOverride(key) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("flag %s is not overridden", key)})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) clearOverrides(w http.ResponseWriter, r *http.Request) {
+	h.provider.ClearOverrides()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) flag(key string) (provider.Flag, bool) {
+	key = strings.ToLower(key)
+	for _, f := range h.provider.Flags() {
+		if f.FlagKey == key {
+			return f, true
+		}
+	}
+	return provider.Flag{}, false
+}
+
+func (h *Handler) ttl(s string) (time.Duration, error) {
+	if s == "" {
+		return h.defaultTTL, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl: %w", err)
+	}
+	if ttl <= 0 {
+		return 0, errors.New("ttl must be positive")
+	}
+	if ttl > h.maxTTL {
+		return 0, fmt.Errorf("ttl must not exceed %s", h.maxTTL)
+	}
+	return ttl, nil
+}
+
+// rawValue converts the value of an override request into the raw string a source
+// would hold, so that "true", true and "10", 10 override alike.
+func rawValue(msg json.RawMessage) (string, error) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 || bytes.Equal(msg, []byte("null")) {
+		return "", errors.New("value is required")
+	}
+	if msg[0] == '"' {
+		var s string
+		if err := json.Unmarshal(msg, &s); err != nil {
+			return "", fmt.Errorf("invalid value: %w", err)
+		}
+		return s, nil
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, msg); err != nil {
+		return "", fmt.Errorf("invalid value: %w", err)
+	}
+	return compact.String(), nil
+}
+
+func toFlagResponse(f provider.Flag) flagResponse {
+	resp := flagResponse{
+		Key:   f.FlagKey,
+		Raw:   f.Raw,
+		Type:  f.Type,
+		Value: f.Value,
+		File:  f.File,
+	}
+	if f.Err != nil {
+		resp.Error = f.Err.Error()
+	}
+	if f.Source != nil {
+		src := toFlagResponse(*f.Source)
+		resp.Source = &src
+	}
+	if o := f.Override; o != nil {
+		resp.Override = &overrideResponse{Value: o.Value}
+		if !o.ExpiresAt.IsZero() {
+			expiresAt := o.ExpiresAt
+			resp.Override.ExpiresAt = &expiresAt
+		}
+	}
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
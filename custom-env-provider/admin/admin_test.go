@@ -0,0 +1,210 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	var got map[string]interface{}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return rec.Code, got
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := provider.NewSimpleEnvProvider(
+		provider.WithSource(provider.MapSource{"FT_KILL_SWITCH": "false", "FT_LIMIT": "10"}),
+		provider.WithClock(func() time.Time { return now }),
+	)
+	h := NewHandler(p)
+
+	status, got := do(t, h, http.MethodGet, "/flags", "")
+	if status != http.StatusOK {
+		t.Errorf("GET /flags status = %d, want %d", status, http.StatusOK)
+	}
+	want := map[string]interface{}{
+		"flags": []interface{}{
+			map[string]interface{}{"key": "kill_switch", "raw": "false", "type": "boolean", "value": false},
+			map[string]interface{}{"key": "limit", "raw": "10", "type": "integer", "value": float64(10)},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GET /flags mismatch (-want +got):\n%s", diff)
+	}
+
+	status, got = do(t, h, http.MethodPut, "/flags/kill_switch/override", `{"value":true,"ttl":"30m"}`)
+	if status != http.StatusOK {
+		t.Errorf("PUT override status = %d, want %d", status, http.StatusOK)
+	}
+	wantFlag := map[string]interface{}{
+		"key":      "kill_switch",
+		"raw":      "true",
+		"type":     "boolean",
+		"value":    true,
+		"override": map[string]interface{}{"value": "true", "expiresAt": "2024-01-01T00:30:00Z"},
+		"source":   map[string]interface{}{"key": "kill_switch", "raw": "false", "type": "boolean", "value": false},
+	}
+	if diff := cmp.Diff(wantFlag, got); diff != "" {
+		t.Errorf("PUT override mismatch (-want +got):\n%s", diff)
+	}
+	if got := p.BooleanEvaluation(context.Background(), "kill_switch", false, openfeature.FlattenedContext{}); !got.Value {
+		t.Error("BooleanEvaluation() after override = false, want true")
+	}
+
+	status, got = do(t, h, http.MethodGet, "/flags/KILL_SWITCH", "")
+	if status != http.StatusOK {
+		t.Errorf("GET flag status = %d, want %d", status, http.StatusOK)
+	}
+	if diff := cmp.Diff(wantFlag, got); diff != "" {
+		t.Errorf("GET flag mismatch (-want +got):\n%s", diff)
+	}
+
+	if status, _ := do(t, h, http.MethodDelete, "/flags/kill_switch/override", ""); status != http.StatusNoContent {
+		t.Errorf("DELETE override status = %d, want %d", status, http.StatusNoContent)
+	}
+	if status, _ := do(t, h, http.MethodDelete, "/flags/kill_switch/override", ""); status != http.StatusNotFound {
+		t.Errorf("DELETE of a missing override status = %d, want %d", status, http.StatusNotFound)
+	}
+	if got := p.BooleanEvaluation(context.Background(), "kill_switch", true, openfeature.FlattenedContext{}); got.Value {
+		t.Error("BooleanEvaluation() after clearing = true, want false")
+	}
+
+	if status, _ := do(t, h, http.MethodPut, "/flags/limit/override", `{"value":"20"}`); status != http.StatusOK {
+		t.Errorf("PUT override status = %d, want %d", status, http.StatusOK)
+	}
+	if diff := cmp.Diff([]provider.Override{{FlagKey: "limit", Value: "20", ExpiresAt: now.Add(DefaultTTL)}}, p.Overrides()); diff != "" {
+		t.Errorf("Overrides() mismatch (-want +got):\n%s", diff)
+	}
+	if status, _ := do(t, h, http.MethodDelete, "/overrides", ""); status != http.StatusNoContent {
+		t.Errorf("DELETE /overrides status = %d, want %d", status, http.StatusNoContent)
+	}
+	if got := p.Overrides(); len(got) != 0 {
+		t.Errorf("Overrides() after clearing = %v, want none", got)
+	}
+}
+
+func TestHandlerRedactsFiles(t *testing.T) {
+	t.Parallel()
+
+	secret := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secret, []byte("s3cret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := provider.NewSimpleEnvProvider(provider.WithSource(provider.MapSource{"FT_TOKEN_FILE": secret}))
+	if err := p.SetOverride("token", "other", 0); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+
+	status, got := do(t, NewHandler(p), http.MethodGet, "/flags/token", "")
+	if status != http.StatusOK {
+		t.Errorf("GET flag status = %d, want %d", status, http.StatusOK)
+	}
+	want := map[string]interface{}{
+		"key":      "token",
+		"raw":      "other",
+		"type":     "string",
+		"value":    "other",
+		"override": map[string]interface{}{"value": "other"},
+		"source":   map[string]interface{}{"key": "token", "raw": "", "type": "string", "file": "FT_TOKEN_FILE"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GET flag mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(provider.NewSimpleEnvProvider(provider.WithSource(provider.MapSource{"FT_LIMIT": "10"})), WithMaxTTL(time.Hour))
+
+	for name, test := range map[string]struct {
+		method, path, body string
+		wantStatus         int
+		wantError          string
+	}{
+		"unknown flag": {
+			method:     http.MethodGet,
+			path:       "/flags/missing",
+			wantStatus: http.StatusNotFound,
+			wantError:  "flag missing not found",
+		},
+		"invalid body": {
+			method:     http.MethodPut,
+			path:       "/flags/limit/override",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid request body: unexpected EOF",
+		},
+		"missing value": {
+			method:     http.MethodPut,
+			path:       "/flags/limit/override",
+			body:       `{"ttl":"1m"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "value is required",
+		},
+		"invalid ttl": {
+			method:     http.MethodPut,
+			path:       "/flags/limit/override",
+			body:       `{"value":1,"ttl":"soon"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  `invalid ttl: time: invalid duration "soon"`,
+		},
+		"negative ttl": {
+			method:     http.MethodPut,
+			path:       "/flags/limit/override",
+			body:       `{"value":1,"ttl":"-1m"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "ttl must be positive",
+		},
+		"ttl too long": {
+			method:     http.MethodPut,
+			path:       "/flags/limit/override",
+			body:       `{"value":1,"ttl":"2h"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "ttl must not exceed 1h0m0s",
+		},
+		"invalid value": {
+			method:     http.MethodPut,
+			path:       "/flags/limit/override",
+			body:       `{"value":"[1,"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "limit: invalid JSON: unexpected end of JSON input",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status, got := do(t, h, test.method, test.path, test.body)
+			if status != test.wantStatus {
+				t.Errorf("status = %d, want %d", status, test.wantStatus)
+			}
+			if diff := cmp.Diff(map[string]interface{}{"error": test.wantError}, got); diff != "" {
+				t.Errorf("response mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
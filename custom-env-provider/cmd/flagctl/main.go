@@ -0,0 +1,204 @@
+// Command flagctl inspects the flags a SimpleEnvProvider would serve.
+//
+// Usage:
+//
+//	flagctl [-prefix FT_] [-env-file .env] list
+//	flagctl [-prefix FT_] [-env-file .env] validate
+//	flagctl [-prefix FT_] [-env-file .env] eval [-type auto] [-context '{"targetingKey":"user-1"}'] <flag>
+//
+// list prints every flag with its detected type and value, validate reports the
+// flags that can never be served and exits non-zero if there are any, and eval
+// prints the full resolution detail of one flag as JSON, exiting non-zero if the
+// evaluation failed.
+//
+// Flags are read from the process environment unless -env-file is given.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// errUsage is returned by commands invoked incorrectly; their flag set has already
+// printed why.
+var errUsage = errors.New("usage")
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("flagctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	prefix := fs.String("prefix", provider.DefaultPrefix, "prefix of flag variables")
+	envFile := fs.String("env-file", "", "read flags from this dotenv file instead of the environment")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: flagctl [options] list | validate | eval [-type t] [-context json] <flag>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	opts := []provider.ProviderOption{provider.WithPrefix(*prefix)}
+	if *envFile != "" {
+		opts = append(opts, provider.WithEnvFile(*envFile))
+	}
+	p := provider.NewSimpleEnvProvider(opts...)
+	if err := p.Reload(); err != nil {
+		fmt.Fprintf(stderr, "flagctl: %v\n", err)
+		return 1
+	}
+
+	var err error
+	switch cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]; cmd {
+	case "list":
+		err = list(p, stdout)
+	case "validate":
+		err = validate(p, stdout)
+	case "eval":
+		err = eval(p, cmdArgs, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "flagctl: unknown command %q\n", cmd)
+		fs.Usage()
+		return 2
+	}
+	switch {
+	case errors.Is(err, errUsage):
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "flagctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func list(p *provider.SimpleEnvProvider, stdout io.Writer) error {
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FLAG\tTYPE\tVALUE")
+	for _, f := range p.Flags() {
+		typ, val := f.Type, f.Raw
+		if f.File != "" {
+			val = "(read from " + f.File + ")"
+		}
+		if f.Err != nil {
+			typ, val = "invalid", f.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.FlagKey, typ, oneLine(val))
+	}
+	return w.Flush()
+}
+
+// oneLine collapses a multi-line value such as a variant document so that list
+// prints one row per flag.
+func oneLine(s string) string {
+	var buf bytes.Buffer
+	if json.Compact(&buf, []byte(s)) == nil {
+		return buf.String()
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// validate checks every flag the way Init does: values that can never be served,
+// and prerequisites on missing flags or in cycles.
+func validate(p *provider.SimpleEnvProvider, stdout io.Writer) error {
+	err := p.Init(openfeature.EvaluationContext{})
+	p.Shutdown()
+
+	var initErr *openfeature.ProviderInitError
+	if errors.As(err, &initErr) {
+		for _, line := range strings.Split(initErr.Message, "\n") {
+			fmt.Fprintln(stdout, line)
+		}
+		return errors.New("invalid flags")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%d flags valid\n", len(p.FlagKeys()))
+	return nil
+}
+
+// evalResult is the resolution detail eval prints.
+type evalResult struct {
+	FlagKey      string                   `json:"flagKey"`
+	Type         string                   `json:"type"`
+	Value        interface{}              `json:"value"`
+	Reason       openfeature.Reason       `json:"reason,omitempty"`
+	Variant      string                   `json:"variant,omitempty"`
+	ErrorCode    openfeature.ErrorCode    `json:"errorCode,omitempty"`
+	ErrorMessage string                   `json:"errorMessage,omitempty"`
+	Metadata     openfeature.FlagMetadata `json:"metadata,omitempty"`
+}
+
+func eval(p *provider.SimpleEnvProvider, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typ := fs.String("type", "auto", "type to evaluate as: auto, "+strings.Join(provider.AutoTypes(), ", "))
+	rawCtx := fs.String("context", "", "evaluation context as a JSON object")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: flagctl eval [-type t] [-context json] <flag>")
+		return errUsage
+	}
+	flagKey := fs.Arg(0)
+
+	var err error
+	evalCtx := openfeature.FlattenedContext{}
+	if *rawCtx != "" {
+		if evalCtx, err = provider.DecodeContext(strings.NewReader(*rawCtx)); err != nil {
+			return fmt.Errorf("invalid context: %w", err)
+		}
+	}
+
+	var r provider.Resolution
+	if *typ == "auto" {
+		r = provider.EvaluateAuto(context.Background(), p, flagKey, evalCtx)
+	} else if r, err = provider.Evaluate(context.Background(), p, flagKey, *typ, evalCtx); err != nil {
+		return err
+	}
+	res := result(flagKey, r)
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		return err
+	}
+	if res.ErrorCode != "" {
+		return fmt.Errorf("evaluate %s: %s", flagKey, res.ErrorCode)
+	}
+	return nil
+}
+
+// result converts a resolution into the detail eval prints.
+func result(flagKey string, res provider.Resolution) evalResult {
+	rd := res.ResolutionDetail()
+	return evalResult{
+		FlagKey:      flagKey,
+		Type:         res.Type,
+		Value:        res.Value,
+		Reason:       rd.Reason,
+		Variant:      rd.Variant,
+		ErrorCode:    rd.ErrorCode,
+		ErrorMessage: rd.ErrorMessage,
+		Metadata:     rd.FlagMetadata,
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeEnvFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const validEnv = `FT_ENABLED=true
+FT_LIMIT=10
+FT_RATIO=0.5
+FT_NAME=checkout
+FT_TIERED={"variants":{"free":1,"paid":2},"defaultVariant":"free","rules":[{"if":{"attribute":"plan","op":"eq","value":"paid"},"variant":"paid"}]}
+OTHER=ignored
+`
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	valid := writeEnvFile(t, validEnv)
+	invalid := writeEnvFile(t, "FT_OK=1\nFT_BAD='[1,'\nFT_DOC={\"variants\":{\"a\":1},\"defaultVariant\":\"b\"}\n")
+
+	for name, test := range map[string]struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		"list": {
+			args:     []string{"-env-file", valid, "list"},
+			wantCode: 0,
+			wantStdout: `FLAG     TYPE      VALUE
+enabled  boolean   true
+limit    integer   10
+name     string    checkout
+ratio    float     0.5
+tiered   document  {"variants":{"free":1,"paid":2},"defaultVariant":"free","rules":[{"if":{"attribute":"plan","op":"eq","value":"paid"},"variant":"paid"}]}
+`,
+		},
+		"list invalid": {
+			args:     []string{"-env-file", invalid, "list"},
+			wantCode: 0,
+			wantStdout: `FLAG  TYPE     VALUE
+bad   invalid  invalid JSON: unexpected end of JSON input
+doc   invalid  default variant "b" is not defined
+ok    integer  1
+`,
+		},
+		"validate": {
+			args:       []string{"-env-file", valid, "validate"},
+			wantCode:   0,
+			wantStdout: "5 flags valid\n",
+		},
+		"validate invalid": {
+			args:       []string{"-env-file", invalid, "validate"},
+			wantCode:   1,
+			wantStdout: "FT_BAD: invalid JSON: unexpected end of JSON input\nFT_DOC: default variant \"b\" is not defined\n",
+			wantStderr: "flagctl: invalid flags\n",
+		},
+		"eval auto": {
+			args:     []string{"-env-file", valid, "eval", "-context", `{"targetingKey":"user-1","plan":"paid"}`, "tiered"},
+			wantCode: 0,
+			wantStdout: `{
+  "flagKey": "tiered",
+  "type": "int",
+  "value": 2,
+  "reason": "TARGETING_MATCH",
+  "variant": "paid",
+  "metadata": {
+    "ruleIndex": 0
+  }
+}
+`,
+		},
+		"eval auto string": {
+			args:     []string{"-env-file", valid, "eval", "name"},
+			wantCode: 0,
+			wantStdout: `{
+  "flagKey": "name",
+  "type": "string",
+  "value": "checkout",
+  "reason": "env"
+}
+`,
+		},
+		"eval type": {
+			args:     []string{"-env-file", valid, "eval", "-type", "bool", "limit"},
+			wantCode: 1,
+			wantStdout: `{
+  "flagKey": "limit",
+  "type": "bool",
+  "value": false,
+  "reason": "ERROR",
+  "errorCode": "PARSE_ERROR",
+  "errorMessage": "strconv.ParseBool: parsing \"10\": invalid syntax"
+}
+`,
+			wantStderr: "flagctl: evaluate limit: PARSE_ERROR\n",
+		},
+		"eval missing": {
+			args:     []string{"-env-file", valid, "eval", "missing"},
+			wantCode: 1,
+			wantStdout: `{
+  "flagKey": "missing",
+  "type": "int",
+  "value": 0,
+  "reason": "ERROR",
+  "errorCode": "FLAG_NOT_FOUND",
+  "errorMessage": "flag FT_MISSING is not set"
+}
+`,
+			wantStderr: "flagctl: evaluate missing: FLAG_NOT_FOUND\n",
+		},
+		"eval invalid context": {
+			args:       []string{"-env-file", valid, "eval", "-context", `{`, "enabled"},
+			wantCode:   1,
+			wantStderr: "flagctl: invalid context: unexpected EOF\n",
+		},
+		"eval without flag": {
+			args:       []string{"-env-file", valid, "eval"},
+			wantCode:   2,
+			wantStderr: "usage: flagctl eval [-type t] [-context json] <flag>\n",
+		},
+		"missing env file": {
+			args:     []string{"-env-file", filepath.Join(t.TempDir(), "missing"), "list"},
+			wantCode: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			code := run(test.args, &stdout, &stderr)
+			if code != test.wantCode {
+				t.Errorf("run() = %d, want %d", code, test.wantCode)
+			}
+			if diff := cmp.Diff(test.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("stdout mismatch (-want +got):\n%s", diff)
+			}
+			if test.wantStderr != "" {
+				if diff := cmp.Diff(test.wantStderr, stderr.String()); diff != "" {
+					t.Errorf("stderr mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
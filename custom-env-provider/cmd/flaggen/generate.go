@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// manifest lists the flags to generate accessors for.
+type manifest struct {
+	// Package is the name of the generated package.
+	Package string          `json:"package"`
+	Flags   []manifestEntry `json:"flags"`
+}
+
+type manifestEntry struct {
+	Key         string          `json:"key"`
+	Type        string          `json:"type"`
+	Default     json.RawMessage `json:"default"`
+	Description string          `json:"description"`
+}
+
+// flagType describes how a manifest type is evaluated and declared in Go.
+type flagType struct {
+	goType  string
+	method  string // client method, e.g. Boolean, also used for <method>ValueDetails
+	details string // openfeature type of the evaluation details
+}
+
+var flagTypes = map[string]flagType{
+	"boolean": {goType: "bool", method: "Boolean", details: "BooleanEvaluationDetails"},
+	"string":  {goType: "string", method: "String", details: "StringEvaluationDetails"},
+	"integer": {goType: "int64", method: "Int", details: "IntEvaluationDetails"},
+	"float":   {goType: "float64", method: "Float", details: "FloatEvaluationDetails"},
+	"object":  {goType: "interface{}", method: "Object", details: "InterfaceEvaluationDetails"},
+}
+
+// accessor is a flag as the template renders it.
+type accessor struct {
+	Key     string
+	Name    string
+	Doc     []string
+	Default string
+	flagType
+}
+
+// identifiers are the names the accessor declares in the generated package.
+func (a accessor) identifiers() []string {
+	return []string{a.Name, a.Name + "Key", a.Name + "Details"}
+}
+
+// clash reports an identifier of a that names already maps to the key of another flag,
+// as keys checkout and checkout_key both declare CheckoutKey.
+func clash(names map[string]string, a accessor) (ident, other string, ok bool) {
+	for _, ident := range a.identifiers() {
+		if other, ok := names[ident]; ok {
+			return ident, other, true
+		}
+	}
+	return "", "", false
+}
+
+func (a accessor) GoType() string  { return a.goType }
+func (a accessor) Method() string  { return a.method }
+func (a accessor) Details() string { return a.details }
+
+func parseManifest(r io.Reader) (*manifest, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var m manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// generate renders the accessors of m as a formatted Go source file.
+func generate(m *manifest) ([]byte, error) {
+	if !token.IsIdentifier(m.Package) {
+		return nil, fmt.Errorf("package %q is not a valid Go identifier", m.Package)
+	}
+
+	var (
+		accessors []accessor
+		errs      []error
+	)
+	keys := make(map[string]bool)
+	names := make(map[string]string)
+	for i, entry := range m.Flags {
+		a, err := newAccessor(entry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("flags[%d]: %w", i, err))
+			continue
+		}
+		if keys[a.Key] {
+			errs = append(errs, fmt.Errorf("flags[%d]: duplicate key %s", i, a.Key))
+			continue
+		}
+		if ident, other, ok := clash(names, a); ok {
+			errs = append(errs, fmt.Errorf("flags[%d]: %s and %s both generate %s", i, other, a.Key, ident))
+			continue
+		}
+		keys[a.Key] = true
+		for _, ident := range a.identifiers() {
+			names[ident] = a.Key
+		}
+		accessors = append(accessors, a)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	sort.Slice(accessors, func(i, j int) bool {
+		return accessors[i].Key < accessors[j].Key
+	})
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, struct {
+		Package   string
+		Accessors []accessor
+	}{m.Package, accessors}); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+func newAccessor(entry manifestEntry) (accessor, error) {
+	if entry.Key == "" {
+		return accessor{}, errors.New("key is required")
+	}
+	ft, ok := flagTypes[entry.Type]
+	if !ok {
+		return accessor{}, fmt.Errorf("%s: unknown type %q", entry.Key, entry.Type)
+	}
+	name := exportedName(entry.Key)
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return accessor{}, fmt.Errorf("%s: key does not make a Go identifier", entry.Key)
+	}
+	def, err := defaultLiteral(entry.Type, entry.Default)
+	if err != nil {
+		return accessor{}, fmt.Errorf("%s: %w", entry.Key, err)
+	}
+
+	var doc []string
+	if desc := strings.TrimSpace(entry.Description); desc != "" {
+		doc = strings.Split(desc, "\n")
+	}
+	return accessor{
+		Key:      entry.Key,
+		Name:     name,
+		Doc:      doc,
+		Default:  def,
+		flagType: ft,
+	}, nil
+}
+
+// initialisms are the words exportedName upper-cases as a whole, as golint would.
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "TLS": true,
+	"TTL": true, "UI": true, "URL": true, "UUID": true,
+}
+
+// exportedName converts a flag key such as "my_feature" or "new-api.url" into an
+// exported Go name such as MyFeature or NewAPIURL.
+func exportedName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(w)
+		b.WriteRune(unicode.ToUpper(first))
+		b.WriteString(w[size:])
+	}
+	return b.String()
+}
+
+// defaultLiteral renders a manifest default as a Go expression of the flag's type.
+// A missing default is the zero value.
+func defaultLiteral(typ string, raw json.RawMessage) (string, error) {
+	var val interface{}
+	if len(raw) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&val); err != nil {
+			return "", fmt.Errorf("invalid default: %w", err)
+		}
+	}
+
+	switch typ {
+	case "boolean":
+		if val == nil {
+			return "false", nil
+		}
+		b, ok := val.(bool)
+		if !ok {
+			return "", fmt.Errorf("default %s is not a boolean", raw)
+		}
+		return strconv.FormatBool(b), nil
+	case "string":
+		if val == nil {
+			return `""`, nil
+		}
+		s, ok := val.(string)
+		if !ok {
+			return "", fmt.Errorf("default %s is not a string", raw)
+		}
+		return strconv.Quote(s), nil
+	case "integer":
+		if val == nil {
+			return "0", nil
+		}
+		n, ok := val.(json.Number)
+		if !ok {
+			return "", fmt.Errorf("default %s is not an integer", raw)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return "", fmt.Errorf("default %s is not an integer", raw)
+		}
+		return strconv.FormatInt(i, 10), nil
+	case "float":
+		if val == nil {
+			return "0", nil
+		}
+		n, ok := val.(json.Number)
+		if !ok {
+			return "", fmt.Errorf("default %s is not a number", raw)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return "", fmt.Errorf("default %s is not a number", raw)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	default:
+		return goLiteral(val), nil
+	}
+}
+
+// goLiteral renders a decoded JSON value as a Go expression, with objects as
+// map[string]interface{} and arrays as []interface{} like encoding/json decodes them.
+func goLiteral(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return strconv.Quote(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return "float64(" + strconv.FormatFloat(f, 'g', -1, 64) + ")"
+	case []interface{}:
+		elems := make([]string, len(v))
+		for i, e := range v {
+			elems[i] = goLiteral(e)
+		}
+		return "[]interface{}{" + strings.Join(elems, ", ") + "}"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elems := make([]string, len(keys))
+		for i, k := range keys {
+			elems[i] = strconv.Quote(k) + ": " + goLiteral(v[k])
+		}
+		return "map[string]interface{}{" + strings.Join(elems, ", ") + "}"
+	}
+	panic(fmt.Sprintf("unexpected JSON value %T", val))
+}
+
+var fileTemplate = template.Must(template.New("flags").Parse(`// Code generated by flaggen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Flag keys.
+const (
+{{- range .Accessors}}
+	{{.Name}}Key = {{printf "%q" .Key}}
+{{- end}}
+)
+{{range .Accessors}}
+// {{.Name}} evaluates the {{.Key}} flag, falling back to {{.Default}}.
+{{- if .Doc}}
+//
+{{- range .Doc}}
+// {{.}}
+{{- end}}
+{{- end}}
+func {{.Name}}(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) {{.GoType}} {
+	return client.{{.Method}}(ctx, {{.Name}}Key, {{.Default}}, evalCtx)
+}
+
+// {{.Name}}Details evaluates the {{.Key}} flag, returning the full evaluation details.
+func {{.Name}}Details(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) (openfeature.{{.Details}}, error) {
+	return client.{{.Method}}ValueDetails(ctx, {{.Name}}Key, {{.Default}}, evalCtx)
+}
+{{end}}`))
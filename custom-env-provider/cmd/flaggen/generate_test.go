@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestGeneratedFlags checks that the flags package is up to date with its manifest.
+func TestGeneratedFlags(t *testing.T) {
+	t.Parallel()
+
+	want, err := os.ReadFile("../../flags/flags_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := generateFile("../../flags/flags.json", "", &got); err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+	if diff := cmp.Diff(string(want), got.String()); diff != "" {
+		t.Errorf("flags/flags_gen.go is out of date, run go generate ./flags (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	m, err := parseManifest(strings.NewReader(`{
+		"package": "features",
+		"flags": [
+			{"key": "checkout.api-url", "type": "string", "default": "https://example.com"},
+			{"key": "ratio", "type": "float", "default": 0.25, "description": "Share of traffic.\nApplied per request."},
+			{"key": "limits", "type": "object", "default": {"max": 10, "tiers": ["free", "paid"], "strict": true}},
+			{"key": "user_id_list", "type": "object"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+	src, err := generate(m)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"package features\n",
+		"CheckoutAPIURLKey = \"checkout.api-url\"",
+		"func CheckoutAPIURL(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) string {\n\treturn client.String(ctx, CheckoutAPIURLKey, \"https://example.com\", evalCtx)",
+		"// Ratio evaluates the ratio flag, falling back to 0.25.\n//\n// Share of traffic.\n// Applied per request.\n",
+		"func RatioDetails(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) (openfeature.FloatEvaluationDetails, error) {\n\treturn client.FloatValueDetails(ctx, RatioKey, 0.25, evalCtx)",
+		`return client.Object(ctx, LimitsKey, map[string]interface{}{"max": float64(10), "strict": true, "tiers": []interface{}{"free", "paid"}}, evalCtx)`,
+		"return client.Object(ctx, UserIDListKey, nil, evalCtx)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		manifest string
+		want     string
+	}{
+		"unknown field": {
+			manifest: `{"package": "flags", "flgas": []}`,
+			want:     `parse manifest: json: unknown field "flgas"`,
+		},
+		"invalid package": {
+			manifest: `{"package": "my-flags"}`,
+			want:     `package "my-flags" is not a valid Go identifier`,
+		},
+		"missing key": {
+			manifest: `{"package": "flags", "flags": [{"type": "boolean"}]}`,
+			want:     "flags[0]: key is required",
+		},
+		"unknown type": {
+			manifest: `{"package": "flags", "flags": [{"key": "a", "type": "bool"}]}`,
+			want:     `flags[0]: a: unknown type "bool"`,
+		},
+		"key without letters": {
+			manifest: `{"package": "flags", "flags": [{"key": "1st", "type": "boolean"}]}`,
+			want:     "flags[0]: 1st: key does not make a Go identifier",
+		},
+		"mistyped default": {
+			manifest: `{"package": "flags", "flags": [{"key": "a", "type": "integer", "default": 1.5}]}`,
+			want:     "flags[0]: a: default 1.5 is not an integer",
+		},
+		"duplicate key": {
+			manifest: `{"package": "flags", "flags": [{"key": "a", "type": "boolean"}, {"key": "a", "type": "string"}]}`,
+			want:     "flags[1]: duplicate key a",
+		},
+		"colliding names": {
+			manifest: `{"package": "flags", "flags": [{"key": "my_feature", "type": "boolean"}, {"key": "my-feature", "type": "boolean"}]}`,
+			want:     "flags[1]: my_feature and my-feature both generate MyFeature",
+		},
+		"colliding accessors": {
+			manifest: `{"package": "flags", "flags": [{"key": "checkout", "type": "boolean"}, {"key": "checkout_details", "type": "boolean"}, {"key": "checkout_key", "type": "string"}]}`,
+			want:     "flags[1]: checkout and checkout_details both generate CheckoutDetails\nflags[2]: checkout and checkout_key both generate CheckoutKey",
+		},
+		"colliding accessors in reverse": {
+			manifest: `{"package": "flags", "flags": [{"key": "checkout_key", "type": "string"}, {"key": "checkout", "type": "boolean"}]}`,
+			want:     "flags[1]: checkout_key and checkout both generate CheckoutKey",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := parseManifest(strings.NewReader(test.manifest))
+			if err == nil {
+				_, err = generate(m)
+			}
+			if err == nil {
+				t.Fatalf("generate() error = nil, want %q", test.want)
+			}
+			if diff := cmp.Diff(test.want, err.Error()); diff != "" {
+				t.Errorf("error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	t.Parallel()
+
+	for key, want := range map[string]string{
+		"my_feature":       "MyFeature",
+		"count":            "Count",
+		"new-api.url":      "NewAPIURL",
+		"user_id":          "UserID",
+		"checkoutV2":       "CheckoutV2",
+		"dark mode":        "DarkMode",
+		"__private__flag_": "PrivateFlag",
+		"über_mode":        "ÜberMode",
+	} {
+		if got := exportedName(key); got != want {
+			t.Errorf("exportedName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
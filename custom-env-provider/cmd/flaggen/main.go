@@ -0,0 +1,74 @@
+// Command flaggen generates a Go package of typed flag accessors from a flag
+// manifest, so that a renamed flag or a changed type is a compile error rather
+// than a silently served default.
+//
+// The manifest is JSON:
+//
+//	{
+//	  "package": "flags",
+//	  "flags": [
+//	    {"key": "my_feature", "type": "boolean", "default": false, "description": "Enables my feature."}
+//	  ]
+//	}
+//
+// Types are boolean, string, integer, float and object. For every flag flaggen
+// emits a key constant and two functions, e.g.
+//
+//	const MyFeatureKey = "my_feature"
+//	func MyFeature(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) bool
+//	func MyFeatureDetails(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) (openfeature.BooleanEvaluationDetails, error)
+//
+// It is meant to be run from a go:generate directive:
+//
+//	//go:generate go run github.com/knwoop/open-feature-playground/custom-env-provider/cmd/flaggen -manifest flags.json -out flags_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("flaggen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	manifestPath := fs.String("manifest", "flags.json", "flag manifest to read")
+	out := fs.String("out", "", "file to write the generated code to; stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := generateFile(*manifestPath, *out, stdout); err != nil {
+		fmt.Fprintf(stderr, "flaggen: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func generateFile(manifestPath, out string, stdout io.Writer) error {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	m, err := parseManifest(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", manifestPath, err)
+	}
+	src, err := generate(m)
+	if err != nil {
+		return fmt.Errorf("%s: %w", manifestPath, err)
+	}
+
+	if out == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,126 @@
+// Command ofrep-server serves the flags of a SimpleEnvProvider over the OpenFeature
+// Remote Evaluation Protocol, so that clients in any language can evaluate them.
+//
+// Flags are read from the process environment by default:
+//
+//	FT_NEW_CHECKOUT=true ofrep-server -addr :8016
+//	curl -X POST localhost:8016/ofrep/v1/evaluate/flags/new_checkout -d '{"context":{"targetingKey":"user-1"}}'
+//
+// As the evaluation context comes from the network, flags are served from the
+// source only by default. -precedence lets callers override flags from their
+// context, and -context-overrides limits which flags they may override:
+//
+//	ofrep-server -precedence context-first -context-overrides beta_banner,theme
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/ofrep"
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+func main() {
+	addr, opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "ofrep-server: %v\n", err)
+		}
+		os.Exit(2)
+	}
+
+	pr := provider.NewSimpleEnvProvider(opts...)
+	if err := pr.Init(openfeature.EvaluationContext{}); err != nil {
+		log.Fatal(err)
+	}
+	defer pr.Shutdown()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           ofrep.NewHandler(pr),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("shutdown: %v", err)
+		}
+	}()
+
+	log.Printf("serving OFREP on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// precedences are the values -precedence accepts.
+var precedences = []provider.Precedence{provider.EnvOnly, provider.EnvFirst, provider.ContextFirst, provider.ContextOnly}
+
+// parseFlags parses the command line into the address to listen on and the
+// options of the provider to serve.
+func parseFlags(args []string, stderr io.Writer) (string, []provider.ProviderOption, error) {
+	fs := flag.NewFlagSet("ofrep-server", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8016", "address to listen on")
+	prefix := fs.String("prefix", provider.DefaultPrefix, "prefix of flag variables")
+	envFile := fs.String("env-file", "", "read flags from this dotenv file instead of the environment")
+	dir := fs.String("dir", "", "read flags from the files in this directory instead of the environment")
+	poll := fs.Duration("poll", provider.DefaultPollInterval, "how often to check -env-file or -dir for changes")
+	names := make([]string, len(precedences))
+	for i, pr := range precedences {
+		names[i] = pr.String()
+	}
+	precedence := fs.String("precedence", provider.EnvOnly.String(), "whether the request context may override flags: "+strings.Join(names, ", "))
+	overrides := fs.String("context-overrides", "", "comma-separated flags the request context may override; all if empty")
+	if err := fs.Parse(args); err != nil {
+		return "", nil, err
+	}
+
+	opts := []provider.ProviderOption{
+		provider.WithPrefix(*prefix),
+		provider.WithPollInterval(*poll),
+	}
+	switch {
+	case *envFile != "" && *dir != "":
+		return "", nil, errors.New("-env-file and -dir are mutually exclusive")
+	case *envFile != "":
+		opts = append(opts, provider.WithEnvFile(*envFile))
+	case *dir != "":
+		opts = append(opts, provider.WithDir(*dir))
+	}
+
+	found := false
+	for _, pr := range precedences {
+		if pr.String() == *precedence {
+			opts = append(opts, provider.WithPrecedence(pr))
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", nil, fmt.Errorf("unknown -precedence %q", *precedence)
+	}
+	if *overrides != "" {
+		opts = append(opts, provider.WithContextOverrides(strings.Split(*overrides, ",")...))
+	}
+	return *addr, opts, nil
+}
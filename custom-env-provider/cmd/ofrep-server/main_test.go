@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/ofrep"
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+func TestContextOverrides(t *testing.T) {
+	t.Parallel()
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("FT_KILL_SWITCH=false\nFT_BETA=false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range map[string]struct {
+		args    []string
+		flagKey string
+		want    bool
+	}{
+		"default ignores context": {
+			args:    nil,
+			flagKey: "kill_switch",
+			want:    false,
+		},
+		"context-first": {
+			args:    []string{"-precedence", "context-first"},
+			flagKey: "kill_switch",
+			want:    true,
+		},
+		"context-overrides allowed": {
+			args:    []string{"-precedence", "context-first", "-context-overrides", "beta"},
+			flagKey: "beta",
+			want:    true,
+		},
+		"context-overrides not allowed": {
+			args:    []string{"-precedence", "context-first", "-context-overrides", "beta"},
+			flagKey: "kill_switch",
+			want:    false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, opts, err := parseFlags(append([]string{"-env-file", envFile}, test.args...), io.Discard)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			pr := provider.NewSimpleEnvProvider(opts...)
+			if err := pr.Init(openfeature.EvaluationContext{}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			t.Cleanup(pr.Shutdown)
+			srv := httptest.NewServer(ofrep.NewHandler(pr))
+			t.Cleanup(srv.Close)
+
+			resp, err := http.Post(srv.URL+"/ofrep/v1/evaluate/flags/"+test.flagKey, "application/json",
+				strings.NewReader(`{"context":{"targetingKey":"user-1","kill_switch":true,"beta":true}}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var got struct {
+				Value bool `json:"value"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got.Value); diff != "" {
+				t.Errorf("value mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		args []string
+		want string
+	}{
+		"unknown precedence": {
+			args: []string{"-precedence", "ctx-first"},
+			want: `unknown -precedence "ctx-first"`,
+		},
+		"env-file and dir": {
+			args: []string{"-env-file", ".env", "-dir", "flags"},
+			want: "-env-file and -dir are mutually exclusive",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := parseFlags(test.args, io.Discard)
+			if err == nil {
+				t.Fatalf("parseFlags() error = nil, want %q", test.want)
+			}
+			if diff := cmp.Diff(test.want, err.Error()); diff != "" {
+				t.Errorf("error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package flagd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	StateEnabled  = "ENABLED"
+	StateDisabled = "DISABLED"
+
+	// flagdProperties is the evaluation data key under which flagd exposes the
+	// flag key and evaluation time to targeting rules, e.g. {"var": "$flagd.flagKey"}.
+	flagdProperties   = "$flagd"
+	flagKeyProperty   = "flagKey"
+	timestampProperty = "timestamp"
+)
+
+// flagSet is a flagd flag definition document:
+//
+//	{
+//	  "flags": {
+//	    "new-welcome-banner": {
+//	      "state": "ENABLED",
+//	      "variants": {"on": true, "off": false},
+//	      "defaultVariant": "off",
+//	      "targeting": {"if": [{"$ref": "is-employee"}, "on"]}
+//	    }
+//	  },
+//	  "$evaluators": {
+//	    "is-employee": {"ends_with": [{"var": "email"}, "@example.com"]}
+//	  }
+//	}
+type flagSet struct {
+	Flags      map[string]*flag           `json:"flags"`
+	Evaluators map[string]json.RawMessage `json:"$evaluators"`
+	Metadata   map[string]interface{}     `json:"metadata"`
+}
+
+type flag struct {
+	State          string                 `json:"state"`
+	Variants       map[string]interface{} `json:"variants"`
+	DefaultVariant string                 `json:"defaultVariant"`
+	Targeting      json.RawMessage        `json:"targeting"`
+	Metadata       map[string]interface{} `json:"metadata"`
+
+	// rule is Targeting decoded with $ref evaluators substituted; nil when the flag has no targeting.
+	rule     interface{}
+	metadata openfeature.FlagMetadata
+}
+
+// parseFlagSet decodes a flag definition document and checks the state, variants
+// and targeting of every flag in it. The errors of all bad flags are returned
+// together, and any one of them rejects the whole document.
+func parseFlagSet(b []byte) (*flagSet, error) {
+	var set flagSet
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("invalid flag definitions: %w", err)
+	}
+
+	evaluators := make(map[string]interface{}, len(set.Evaluators))
+	for name, raw := range set.Evaluators {
+		var rule interface{}
+		if err := json.Unmarshal(raw, &rule); err != nil {
+			return nil, fmt.Errorf("evaluator %s: %w", name, err)
+		}
+		evaluators[name] = rule
+	}
+
+	keys := make([]string, 0, len(set.Flags))
+	for key := range set.Flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := set.Flags[key].compile(evaluators, set.Metadata); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+func (f *flag) compile(evaluators map[string]interface{}, setMetadata map[string]interface{}) error {
+	if f == nil {
+		return errors.New("flag is null")
+	}
+	switch f.State {
+	case StateEnabled, StateDisabled:
+	default:
+		return fmt.Errorf("state %q is neither %s nor %s", f.State, StateEnabled, StateDisabled)
+	}
+	if len(f.Variants) == 0 {
+		return errors.New("no variants")
+	}
+	if _, ok := f.Variants[f.DefaultVariant]; !ok {
+		return fmt.Errorf("default variant %q is not defined", f.DefaultVariant)
+	}
+
+	f.metadata = openfeature.FlagMetadata{}
+	for k, v := range setMetadata {
+		f.metadata[k] = v
+	}
+	for k, v := range f.Metadata {
+		f.metadata[k] = v
+	}
+
+	if len(f.Targeting) == 0 {
+		return nil
+	}
+	var rule interface{}
+	if err := json.Unmarshal(f.Targeting, &rule); err != nil {
+		return fmt.Errorf("targeting: %w", err)
+	}
+	rule, err := substituteRefs(rule, evaluators, nil)
+	if err != nil {
+		return fmt.Errorf("targeting: %w", err)
+	}
+	if err := checkOperators(rule); err != nil {
+		return fmt.Errorf("targeting: %w", err)
+	}
+	// An empty targeting object means the flag is not targeted, as in flagd.
+	if m, ok := rule.(map[string]interface{}); ok && len(m) == 0 {
+		return nil
+	}
+	f.rule = rule
+	return nil
+}
+
+// substituteRefs replaces every {"$ref": "name"} in rule with the named shared
+// evaluator. seen holds the evaluators being expanded, to reject cycles.
+func substituteRefs(rule interface{}, evaluators map[string]interface{}, seen []string) (interface{}, error) {
+	switch r := rule.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(r))
+		for i, v := range r {
+			sub, err := substituteRefs(v, evaluators, seen)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = sub
+		}
+		return out, nil
+	case map[string]interface{}:
+		if ref, ok := r["$ref"]; ok && len(r) == 1 {
+			name, _ := ref.(string)
+			for _, s := range seen {
+				if s == name {
+					return nil, fmt.Errorf("evaluator %q refers to itself", name)
+				}
+			}
+			target, ok := evaluators[name]
+			if !ok {
+				return nil, fmt.Errorf("evaluator %q is not defined", name)
+			}
+			return substituteRefs(target, evaluators, append(seen, name))
+		}
+		out := make(map[string]interface{}, len(r))
+		for k, v := range r {
+			sub, err := substituteRefs(v, evaluators, seen)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = sub
+		}
+		return out, nil
+	}
+	return rule, nil
+}
+
+// checkOperators reports operations that apply would not know how to evaluate.
+func checkOperators(rule interface{}) error {
+	switch r := rule.(type) {
+	case []interface{}:
+		for _, v := range r {
+			if err := checkOperators(v); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for op, args := range r {
+			if len(r) == 1 && !knownOperator(op) {
+				return fmt.Errorf("unknown operator %q", op)
+			}
+			if err := checkOperators(args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func knownOperator(op string) bool {
+	switch op {
+	case "if", "?:", "and", "or", "map", "filter", "all", "none", "some", "reduce":
+		return true
+	}
+	_, ok := operators[op]
+	return ok
+}
+
+// evaluation is the variant a flag resolved to and why.
+type evaluation struct {
+	variant  string
+	value    interface{}
+	reason   openfeature.Reason
+	metadata openfeature.FlagMetadata
+}
+
+// evaluate resolves the flag the way flagd does: a disabled flag is reported as
+// such, an untargeted flag is static, and a targeting rule that yields a variant
+// name (or a boolean, for variants named "true" and "false") matches; a rule that
+// yields null falls back to the default variant.
+func (f *flag) evaluate(flagKey string, evalCtx openfeature.FlattenedContext, now time.Time) (evaluation, error) {
+	if f.State == StateDisabled {
+		return evaluation{reason: openfeature.DisabledReason, metadata: f.metadata}, nil
+	}
+
+	if f.rule == nil {
+		return f.variant(f.DefaultVariant, openfeature.StaticReason), nil
+	}
+
+	data := make(map[string]interface{}, len(evalCtx)+1)
+	for k, v := range evalCtx {
+		data[k] = v
+	}
+	data[flagdProperties] = map[string]interface{}{
+		flagKeyProperty:   flagKey,
+		timestampProperty: now.Unix(),
+	}
+
+	res, err := apply(f.rule, data)
+	if err != nil {
+		return evaluation{}, openfeature.NewParseErrorResolutionError(fmt.Sprintf("targeting of %s: %v", flagKey, err))
+	}
+
+	var name string
+	switch v := res.(type) {
+	case nil:
+		return f.variant(f.DefaultVariant, openfeature.DefaultReason), nil
+	case string:
+		name = v
+	case bool:
+		name = strconv.FormatBool(v)
+	default:
+		return evaluation{}, openfeature.NewGeneralResolutionError(fmt.Sprintf("targeting of %s returned %v, not a variant name", flagKey, res))
+	}
+
+	if _, ok := f.Variants[name]; !ok {
+		return evaluation{}, openfeature.NewGeneralResolutionError(fmt.Sprintf("targeting of %s returned undefined variant %q", flagKey, name))
+	}
+	return f.variant(name, openfeature.TargetingMatchReason), nil
+}
+
+func (f *flag) variant(name string, reason openfeature.Reason) evaluation {
+	return evaluation{
+		variant:  name,
+		value:    f.Variants[name],
+		reason:   reason,
+		metadata: f.metadata,
+	}
+}
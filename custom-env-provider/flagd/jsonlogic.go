@@ -0,0 +1,597 @@
+package flagd
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operator implements a JSONLogic operation on already evaluated arguments.
+type operator func(args []interface{}, data interface{}) (interface{}, error)
+
+// operators holds the eagerly evaluated operations. Operations that must not
+// evaluate all of their arguments up front (if, and, or and the array operations)
+// are handled in apply.
+var operators map[string]operator
+
+func init() {
+	operators = map[string]operator{
+		"var":          opVar,
+		"missing":      opMissing,
+		"missing_some": opMissingSome,
+		"==": func(a []interface{}, _ interface{}) (interface{}, error) {
+			return looseEqual(arg(a, 0), arg(a, 1)), nil
+		},
+		"!=": func(a []interface{}, _ interface{}) (interface{}, error) {
+			return !looseEqual(arg(a, 0), arg(a, 1)), nil
+		},
+		"===": func(a []interface{}, _ interface{}) (interface{}, error) {
+			return strictEqual(arg(a, 0), arg(a, 1)), nil
+		},
+		"!==": func(a []interface{}, _ interface{}) (interface{}, error) {
+			return !strictEqual(arg(a, 0), arg(a, 1)), nil
+		},
+		"!":      func(a []interface{}, _ interface{}) (interface{}, error) { return !truthy(arg(a, 0)), nil },
+		"!!":     func(a []interface{}, _ interface{}) (interface{}, error) { return truthy(arg(a, 0)), nil },
+		"<":      compare(true, func(a, b float64) bool { return a < b }),
+		"<=":     compare(true, func(a, b float64) bool { return a <= b }),
+		">":      compare(false, func(a, b float64) bool { return a > b }),
+		">=":     compare(false, func(a, b float64) bool { return a >= b }),
+		"max":    opMax,
+		"min":    opMin,
+		"+":      opAdd,
+		"-":      opSub,
+		"*":      opMul,
+		"/":      opDiv,
+		"%":      opMod,
+		"in":     opIn,
+		"cat":    opCat,
+		"substr": opSubstr,
+		"merge":  opMerge,
+		"log":    func(a []interface{}, _ interface{}) (interface{}, error) { return arg(a, 0), nil },
+
+		// flagd extensions
+		"fractional":  opFractional,
+		"sem_ver":     opSemVer,
+		"starts_with": opStartsWith,
+		"ends_with":   opEndsWith,
+	}
+}
+
+// apply evaluates a JSONLogic rule against data. A JSON object with a single key
+// is an operation; any other value evaluates to itself, with arrays evaluated
+// element by element.
+func apply(rule interface{}, data interface{}) (interface{}, error) {
+	switch r := rule.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(r))
+		for i, v := range r {
+			res, err := apply(v, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = res
+		}
+		return out, nil
+	case map[string]interface{}:
+		if len(r) != 1 {
+			return r, nil
+		}
+		for op, rawArgs := range r {
+			return applyOp(op, argList(rawArgs), data)
+		}
+	}
+	return rule, nil
+}
+
+func applyOp(op string, args []interface{}, data interface{}) (interface{}, error) {
+	switch op {
+	case "if", "?:":
+		return opIf(args, data)
+	case "and":
+		return opAnd(args, data)
+	case "or":
+		return opOr(args, data)
+	case "map", "filter", "all", "none", "some":
+		return opArray(op, args, data)
+	case "reduce":
+		return opReduce(args, data)
+	}
+
+	fn, ok := operators[op]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+
+	evaluated := make([]interface{}, len(args))
+	for i, a := range args {
+		v, err := apply(a, data)
+		if err != nil {
+			return nil, err
+		}
+		evaluated[i] = v
+	}
+	return fn(evaluated, data)
+}
+
+// argList normalises operation arguments: {"op": x} is shorthand for {"op": [x]}.
+func argList(raw interface{}) []interface{} {
+	if list, ok := raw.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{raw}
+}
+
+func arg(args []interface{}, i int) interface{} {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+func opVar(args []interface{}, data interface{}) (interface{}, error) {
+	path := arg(args, 0)
+	if path == nil {
+		return data, nil
+	}
+
+	key := toString(path)
+	if key == "" {
+		return data, nil
+	}
+
+	cur := data
+	for _, part := range strings.Split(key, ".") {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[part]
+			if !ok {
+				return arg(args, 1), nil
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(c) {
+				return arg(args, 1), nil
+			}
+			cur = c[i]
+		default:
+			return arg(args, 1), nil
+		}
+	}
+	if cur == nil {
+		return arg(args, 1), nil
+	}
+	return cur, nil
+}
+
+func opMissing(args []interface{}, data interface{}) (interface{}, error) {
+	if list, ok := arg(args, 0).([]interface{}); ok {
+		args = list
+	}
+
+	missing := []interface{}{}
+	for _, key := range args {
+		v, _ := opVar([]interface{}{key}, data)
+		if v == nil || v == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
+
+func opMissingSome(args []interface{}, data interface{}) (interface{}, error) {
+	need, _ := toNumber(arg(args, 0))
+	keys, _ := arg(args, 1).([]interface{})
+
+	res, _ := opMissing([]interface{}{keys}, data)
+	missing := res.([]interface{})
+	if float64(len(keys)-len(missing)) >= need {
+		return []interface{}{}, nil
+	}
+	return missing, nil
+}
+
+func opIf(args []interface{}, data interface{}) (interface{}, error) {
+	for i := 0; i+1 < len(args); i += 2 {
+		cond, err := apply(args[i], data)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(cond) {
+			return apply(args[i+1], data)
+		}
+	}
+	if len(args)%2 == 1 {
+		return apply(args[len(args)-1], data)
+	}
+	return nil, nil
+}
+
+func opAnd(args []interface{}, data interface{}) (interface{}, error) {
+	var v interface{}
+	for _, a := range args {
+		var err error
+		if v, err = apply(a, data); err != nil {
+			return nil, err
+		}
+		if !truthy(v) {
+			return v, nil
+		}
+	}
+	return v, nil
+}
+
+func opOr(args []interface{}, data interface{}) (interface{}, error) {
+	var v interface{}
+	for _, a := range args {
+		var err error
+		if v, err = apply(a, data); err != nil {
+			return nil, err
+		}
+		if truthy(v) {
+			return v, nil
+		}
+	}
+	return v, nil
+}
+
+// opArray implements map, filter, all, none and some, whose second argument is
+// evaluated once per element with the element as data.
+func opArray(op string, args []interface{}, data interface{}) (interface{}, error) {
+	src, err := apply(arg(args, 0), data)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := src.([]interface{})
+	logic := arg(args, 1)
+
+	switch op {
+	case "map":
+		out := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			v, err := apply(logic, item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case "filter":
+		out := []interface{}{}
+		for _, item := range items {
+			v, err := apply(logic, item)
+			if err != nil {
+				return nil, err
+			}
+			if truthy(v) {
+				out = append(out, item)
+			}
+		}
+		return out, nil
+	}
+
+	matched := 0
+	for _, item := range items {
+		v, err := apply(logic, item)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(v) {
+			matched++
+		}
+	}
+	switch op {
+	case "all":
+		return len(items) > 0 && matched == len(items), nil
+	case "none":
+		return matched == 0, nil
+	default: // some
+		return matched > 0, nil
+	}
+}
+
+func opReduce(args []interface{}, data interface{}) (interface{}, error) {
+	src, err := apply(arg(args, 0), data)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := apply(arg(args, 2), data)
+	if err != nil {
+		return nil, err
+	}
+
+	items, _ := src.([]interface{})
+	for _, item := range items {
+		acc, err = apply(arg(args, 1), map[string]interface{}{"current": item, "accumulator": acc})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// compare builds <, <=, > and >=. With between set, as for < and <=, a third
+// argument tests that the middle one lies between the others; > and >= compare
+// their first two arguments only, as in JSONLogic.
+func compare(between bool, holds func(a, b float64) bool) operator {
+	return func(args []interface{}, _ interface{}) (interface{}, error) {
+		if len(args) < 2 {
+			return false, nil
+		}
+		pairs := 1
+		if between && len(args) > 2 {
+			pairs = 2
+		}
+		for i := 0; i < pairs; i++ {
+			a, ok := toNumber(args[i])
+			if !ok {
+				return false, nil
+			}
+			b, ok := toNumber(args[i+1])
+			if !ok || !holds(a, b) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+func numbers(args []interface{}) ([]float64, bool) {
+	if len(args) == 1 {
+		if list, ok := args[0].([]interface{}); ok {
+			args = list
+		}
+	}
+	out := make([]float64, len(args))
+	for i, a := range args {
+		n, ok := toNumber(a)
+		if !ok {
+			return nil, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+func opMax(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok || len(ns) == 0 {
+		return nil, nil
+	}
+	sort.Float64s(ns)
+	return ns[len(ns)-1], nil
+}
+
+func opMin(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok || len(ns) == 0 {
+		return nil, nil
+	}
+	sort.Float64s(ns)
+	return ns[0], nil
+}
+
+func opAdd(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok {
+		return nil, nil
+	}
+	var sum float64
+	for _, n := range ns {
+		sum += n
+	}
+	return sum, nil
+}
+
+func opSub(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok || len(ns) == 0 {
+		return nil, nil
+	}
+	if len(ns) == 1 {
+		return -ns[0], nil
+	}
+	return ns[0] - ns[1], nil
+}
+
+func opMul(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok || len(ns) == 0 {
+		return nil, nil
+	}
+	product := 1.0
+	for _, n := range ns {
+		product *= n
+	}
+	return product, nil
+}
+
+func opDiv(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok || len(ns) < 2 || ns[1] == 0 {
+		return nil, nil
+	}
+	return ns[0] / ns[1], nil
+}
+
+func opMod(args []interface{}, _ interface{}) (interface{}, error) {
+	ns, ok := numbers(args)
+	if !ok || len(ns) < 2 || ns[1] == 0 {
+		return nil, nil
+	}
+	return math.Mod(ns[0], ns[1]), nil
+}
+
+func opIn(args []interface{}, _ interface{}) (interface{}, error) {
+	switch haystack := arg(args, 1).(type) {
+	case string:
+		return strings.Contains(haystack, toString(arg(args, 0))), nil
+	case []interface{}:
+		for _, v := range haystack {
+			if strictEqual(v, arg(args, 0)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func opCat(args []interface{}, _ interface{}) (interface{}, error) {
+	var b strings.Builder
+	for _, a := range args {
+		b.WriteString(toString(a))
+	}
+	return b.String(), nil
+}
+
+func opSubstr(args []interface{}, _ interface{}) (interface{}, error) {
+	s := []rune(toString(arg(args, 0)))
+	start, _ := toNumber(arg(args, 1))
+	from := int(start)
+	if from < 0 {
+		from = max(len(s)+from, 0)
+	}
+	from = min(from, len(s))
+
+	to := len(s)
+	if length, ok := toNumber(arg(args, 2)); ok && arg(args, 2) != nil {
+		if length < 0 {
+			to = max(len(s)+int(length), from)
+		} else {
+			to = min(from+int(length), len(s))
+		}
+	}
+	return string(s[from:to]), nil
+}
+
+func opMerge(args []interface{}, _ interface{}) (interface{}, error) {
+	out := []interface{}{}
+	for _, a := range args {
+		if list, ok := a.([]interface{}); ok {
+			out = append(out, list...)
+		} else {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// truthy follows JSONLogic: false, null, 0, "" and [] are false.
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []interface{}:
+		return len(t) > 0
+	}
+	if n, ok := toNumber(v); ok {
+		return n != 0
+	}
+	return true
+}
+
+// looseEqual approximates JavaScript's ==, converting numbers, numeric strings and booleans.
+func looseEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return as == bs
+		}
+	}
+	if isScalar(a) && isScalar(b) {
+		an, aok := toNumber(a)
+		bn, bok := toNumber(b)
+		return aok && bok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func strictEqual(a, b interface{}) bool {
+	an, aok := number(a)
+	bn, bok := number(b)
+	if aok || bok {
+		return aok && bok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, bool:
+		return true
+	}
+	_, ok := number(v)
+	return ok
+}
+
+// number converts Go numeric types, and only those, to float64.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// toNumber converts v the way JavaScript's Number() would, for the types JSON has.
+func toNumber(v interface{}) (float64, bool) {
+	if n, ok := number(v); ok {
+		return n, true
+	}
+	switch t := v.(type) {
+	case nil:
+		return 0, true
+	case bool:
+		if t {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		s := strings.TrimSpace(t)
+		if s == "" {
+			return 0, true
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	if n, ok := number(v); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	return fmt.Sprint(v)
+}
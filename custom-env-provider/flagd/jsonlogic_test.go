@@ -0,0 +1,236 @@
+package flagd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApply(t *testing.T) {
+	data := map[string]interface{}{
+		"email":  "dev@example.com",
+		"age":    30.0,
+		"tags":   []interface{}{"beta", "internal"},
+		"nested": map[string]interface{}{"plan": "pro"},
+	}
+
+	for name, test := range map[string]struct {
+		rule string
+		want interface{}
+	}{
+		"literal":              {rule: `"on"`, want: "on"},
+		"var":                  {rule: `{"var":"email"}`, want: "dev@example.com"},
+		"var dotted":           {rule: `{"var":"nested.plan"}`, want: "pro"},
+		"var array index":      {rule: `{"var":"tags.1"}`, want: "internal"},
+		"var default":          {rule: `{"var":["country","JP"]}`, want: "JP"},
+		"var missing":          {rule: `{"var":"country"}`, want: nil},
+		"missing":              {rule: `{"missing":["email","country"]}`, want: []interface{}{"country"}},
+		"missing_some":         {rule: `{"missing_some":[1,["email","country"]]}`, want: []interface{}{}},
+		"loose equality":       {rule: `{"==":[{"var":"age"},"30"]}`, want: true},
+		"strict equality":      {rule: `{"===":[{"var":"age"},"30"]}`, want: false},
+		"not":                  {rule: `{"!":[{"var":"country"}]}`, want: true},
+		"double not":           {rule: `{"!!":[[]]}`, want: false},
+		"between":              {rule: `{"<":[18,{"var":"age"},65]}`, want: true},
+		"gte":                  {rule: `{">=":[{"var":"age"},31]}`, want: false},
+		"and short circuits":   {rule: `{"and":[false,{"unknown":[]}]}`, want: false},
+		"or returns value":     {rule: `{"or":[0,"",{"var":"email"}]}`, want: "dev@example.com"},
+		"if":                   {rule: `{"if":[{"==":[{"var":"nested.plan"},"free"]},"a",{"in":["beta",{"var":"tags"}]},"b","c"]}`, want: "b"},
+		"if without else":      {rule: `{"if":[false,"a"]}`, want: nil},
+		"in string":            {rule: `{"in":["@example",{"var":"email"}]}`, want: true},
+		"arithmetic":           {rule: `{"+":[{"*":[2,3]},{"-":[10,4]},{"/":[9,3]},{"%":[7,4]}]}`, want: 18.0},
+		"max":                  {rule: `{"max":[1,5,3]}`, want: 5.0},
+		"cat":                  {rule: `{"cat":["v",1,".",2]}`, want: "v1.2"},
+		"substr":               {rule: `{"substr":[{"var":"email"},-11]}`, want: "example.com"},
+		"substr length":        {rule: `{"substr":["flagd",1,-1]}`, want: "lag"},
+		"merge":                {rule: `{"merge":[[1],2,[3]]}`, want: []interface{}{1.0, 2.0, 3.0}},
+		"map":                  {rule: `{"map":[[1,2],{"*":[{"var":""},2]}]}`, want: []interface{}{2.0, 4.0}},
+		"filter":               {rule: `{"filter":[[1,2,3],{">":[{"var":""},1]}]}`, want: []interface{}{2.0, 3.0}},
+		"reduce":               {rule: `{"reduce":[[1,2,3],{"+":[{"var":"current"},{"var":"accumulator"}]},0]}`, want: 6.0},
+		"some":                 {rule: `{"some":[{"var":"tags"},{"==":[{"var":""},"beta"]}]}`, want: true},
+		"all of empty":         {rule: `{"all":[[],true]}`, want: false},
+		"none":                 {rule: `{"none":[{"var":"tags"},{"==":[{"var":""},"beta"]}]}`, want: false},
+		"multi-key object":     {rule: `{"a":1,"b":2}`, want: map[string]interface{}{"a": 1.0, "b": 2.0}},
+		"single argument form": {rule: `{"var":"age"}`, want: 30.0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var rule interface{}
+			if err := json.Unmarshal([]byte(test.rule), &rule); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := apply(rule, data)
+			if err != nil {
+				t.Fatalf("apply() error = %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("apply() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// applyRule decodes rule and applies it to data.
+func applyRule(t *testing.T, rule string, data interface{}) interface{} {
+	t.Helper()
+
+	var r interface{}
+	if err := json.Unmarshal([]byte(rule), &r); err != nil {
+		t.Fatal(err)
+	}
+	got, err := apply(r, data)
+	if err != nil {
+		t.Fatalf("apply(%s) error = %v", rule, err)
+	}
+	return got
+}
+
+func TestMissingSome(t *testing.T) {
+	data := map[string]interface{}{"email": "dev@example.com", "age": 30.0, "name": ""}
+
+	for name, test := range map[string]struct {
+		rule string
+		want interface{}
+	}{
+		"enough present":     {rule: `{"missing_some":[2,["email","age","country"]]}`, want: []interface{}{}},
+		"too few present":    {rule: `{"missing_some":[3,["email","age","country"]]}`, want: []interface{}{"country"}},
+		"empty is missing":   {rule: `{"missing_some":[2,["email","name","country"]]}`, want: []interface{}{"name", "country"}},
+		"none needed":        {rule: `{"missing_some":[0,["country"]]}`, want: []interface{}{}},
+		"nested path":        {rule: `{"missing_some":[1,["plan.tier"]]}`, want: []interface{}{"plan.tier"}},
+		"need from variable": {rule: `{"missing_some":[{"var":"age"},["email","country"]]}`, want: []interface{}{"country"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, applyRule(t, test.rule, data)); diff != "" {
+				t.Errorf("apply(%s) mismatch (-want +got):\n%s", test.rule, diff)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	data := map[string]interface{}{"prices": []interface{}{1.5, 2.5}, "offset": 10.0}
+
+	for name, test := range map[string]struct {
+		rule string
+		want interface{}
+	}{
+		"sum":               {rule: `{"reduce":[{"var":"prices"},{"+":[{"var":"current"},{"var":"accumulator"}]},0]}`, want: 4.0},
+		"initial from data": {rule: `{"reduce":[{"var":"prices"},{"+":[{"var":"current"},{"var":"accumulator"}]},{"var":"offset"}]}`, want: 14.0},
+		"empty array":       {rule: `{"reduce":[[],{"+":[{"var":"current"},{"var":"accumulator"}]},7]}`, want: 7.0},
+		"not an array":      {rule: `{"reduce":[{"var":"offset"},{"+":[{"var":"current"},{"var":"accumulator"}]},7]}`, want: 7.0},
+		"no initial value":  {rule: `{"reduce":[[],{"var":"current"}]}`, want: nil},
+		"data is scoped":    {rule: `{"reduce":[[1],{"var":"offset"},0]}`, want: nil},
+		"build an array":    {rule: `{"reduce":[[1,2],{"merge":[{"var":"accumulator"},{"*":[{"var":"current"},2]}]},[]]}`, want: []interface{}{2.0, 4.0}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, applyRule(t, test.rule, data)); diff != "" {
+				t.Errorf("apply(%s) mismatch (-want +got):\n%s", test.rule, diff)
+			}
+		})
+	}
+}
+
+func TestSubstr(t *testing.T) {
+	for name, test := range map[string]struct {
+		rule string
+		want string
+	}{
+		"from start":              {rule: `{"substr":["jsonlogic",4]}`, want: "logic"},
+		"from end":                {rule: `{"substr":["jsonlogic",-5]}`, want: "logic"},
+		"start past the end":      {rule: `{"substr":["flagd",10]}`, want: ""},
+		"start before the start":  {rule: `{"substr":["flagd",-10]}`, want: "flagd"},
+		"length":                  {rule: `{"substr":["jsonlogic",1,3]}`, want: "son"},
+		"length past the end":     {rule: `{"substr":["flagd",3,10]}`, want: "gd"},
+		"zero length":             {rule: `{"substr":["flagd",1,0]}`, want: ""},
+		"negative length":         {rule: `{"substr":["jsonlogic",4,-2]}`, want: "log"},
+		"negative length overlap": {rule: `{"substr":["flagd",3,-4]}`, want: ""},
+		"multi-byte characters":   {rule: `{"substr":["日本語テキスト",1,2]}`, want: "本語"},
+		"number source":           {rule: `{"substr":[12345,1,2]}`, want: "23"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := applyRule(t, test.rule, nil); got != test.want {
+				t.Errorf("apply(%s) = %q, want %q", test.rule, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLooseEqual(t *testing.T) {
+	for name, test := range map[string]struct {
+		a, b interface{}
+		want bool
+	}{
+		"numbers":                 {a: 1.0, b: int64(1), want: true},
+		"number and string":       {a: 1.0, b: "1", want: true},
+		"number and float string": {a: 1.0, b: "1.0", want: true},
+		"different strings":       {a: "1", b: "1.0", want: false},
+		"true and one":            {a: true, b: 1.0, want: true},
+		"false and zero string":   {a: false, b: "0", want: true},
+		"empty string and zero":   {a: "", b: 0.0, want: true},
+		"true and true string":    {a: true, b: "true", want: false},
+		"not a number":            {a: "abc", b: 0.0, want: false},
+		"null and null":           {a: nil, b: nil, want: true},
+		"null and zero":           {a: nil, b: 0.0, want: false},
+		"null and false":          {a: nil, b: false, want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := looseEqual(test.a, test.b); got != test.want {
+				t.Errorf("looseEqual(%#v, %#v) = %v, want %v", test.a, test.b, got, test.want)
+			}
+			if got := looseEqual(test.b, test.a); got != test.want {
+				t.Errorf("looseEqual(%#v, %#v) = %v, want %v", test.b, test.a, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompareBetween(t *testing.T) {
+	for name, test := range map[string]struct {
+		rule string
+		want bool
+	}{
+		"inside":                         {rule: `{"<":[1,2,3]}`, want: true},
+		"at lower bound":                 {rule: `{"<":[1,1,3]}`, want: false},
+		"at upper bound":                 {rule: `{"<":[1,3,3]}`, want: false},
+		"inclusive lower bound":          {rule: `{"<=":[1,1,3]}`, want: true},
+		"inclusive upper bound":          {rule: `{"<=":[1,3,3]}`, want: true},
+		"inclusive outside":              {rule: `{"<=":[1,4,3]}`, want: false},
+		"numeric string":                 {rule: `{"<":[1,"2",3]}`, want: true},
+		"not a number":                   {rule: `{"<":[1,"two",3]}`, want: false},
+		"greater ignores third":          {rule: `{">":[3,2,5]}`, want: true},
+		"greater or equal ignores third": {rule: `{">=":[3,3,5]}`, want: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := applyRule(t, test.rule, nil); got != test.want {
+				t.Errorf("apply(%s) = %v, want %v", test.rule, got, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyUnknownOperator(t *testing.T) {
+	if _, err := apply(map[string]interface{}{"nope": []interface{}{}}, nil); err == nil {
+		t.Error("apply() error = nil, want an error")
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	for name, test := range map[string]struct {
+		val  interface{}
+		want bool
+	}{
+		"nil":          {val: nil, want: false},
+		"zero":         {val: 0.0, want: false},
+		"int":          {val: 3, want: true},
+		"empty string": {val: "", want: false},
+		"zero string":  {val: "0", want: true},
+		"empty array":  {val: []interface{}{}, want: false},
+		"object":       {val: map[string]interface{}{}, want: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := truthy(test.val); got != test.want {
+				t.Errorf("truthy(%v) = %v, want %v", test.val, got, test.want)
+			}
+		})
+	}
+}
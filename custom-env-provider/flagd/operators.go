@@ -0,0 +1,146 @@
+package flagd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/twmb/murmur3"
+	"golang.org/x/mod/semver"
+)
+
+// opFractional implements flagd's fractional operation:
+//
+//	{"fractional": [<bucket by>, ["red", 50], ["blue", 50]]}
+//
+// The optional first argument is the string to bucket by; it defaults to the flag
+// key followed by the targeting key. Weights default to 1 and are relative to
+// their sum. The result is the name of the variant whose range the murmur3 hash of
+// the bucketing value falls in, matching flagd. Like flagd, a missing targeting key
+// yields null so that the flag falls back to its default variant.
+func opFractional(args []interface{}, data interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("fractional: no distribution")
+	}
+
+	bucketBy, ok := args[0].(string)
+	if ok {
+		args = args[1:]
+	} else {
+		ctx, _ := data.(map[string]interface{})
+		targetingKey, ok := ctx[openfeature.TargetingKey].(string)
+		if !ok {
+			return nil, nil
+		}
+		flagd, _ := ctx[flagdProperties].(map[string]interface{})
+		flagKey, _ := flagd[flagKeyProperty].(string)
+		bucketBy = flagKey + targetingKey
+	}
+
+	type share struct {
+		variant string
+		weight  float64
+	}
+	shares := make([]share, 0, len(args))
+	var total float64
+	for _, a := range args {
+		dist, ok := a.([]interface{})
+		if !ok || len(dist) == 0 {
+			return nil, fmt.Errorf("fractional: distribution %v is not [variant, weight]", a)
+		}
+		variant, ok := dist[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("fractional: variant %v is not a string", dist[0])
+		}
+		weight := 1.0
+		if len(dist) > 1 {
+			if weight, ok = number(dist[1]); !ok {
+				return nil, fmt.Errorf("fractional: weight of %s is not a number", variant)
+			}
+		}
+		shares = append(shares, share{variant: variant, weight: weight})
+		total += weight
+	}
+
+	hash := int32(murmur3.StringSum32(bucketBy))
+	bucket := math.Abs(float64(hash)) / math.MaxInt32 * total
+
+	var end float64
+	for _, s := range shares {
+		end += s.weight
+		if bucket < end {
+			return s.variant, nil
+		}
+	}
+	return nil, nil
+}
+
+// opSemVer implements flagd's sem_ver operation:
+//
+//	{"sem_ver": [{"var": "version"}, ">=", "1.0.0"]}
+//
+// Operators are =, !=, <, <=, >, >=, ^ (same major version) and ~ (same minor version).
+// As in flagd, a version that does not parse is an error rather than a mismatch.
+func opSemVer(args []interface{}, _ interface{}) (interface{}, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("sem_ver: want 3 arguments, got %d", len(args))
+	}
+	a, ok := canonicalSemver(args[0])
+	if !ok {
+		return nil, fmt.Errorf("sem_ver: %v is not a semantic version", args[0])
+	}
+	op, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("sem_ver: operator %v is not a string", args[1])
+	}
+	b, ok := canonicalSemver(args[2])
+	if !ok {
+		return nil, fmt.Errorf("sem_ver: %v is not a semantic version", args[2])
+	}
+
+	cmp := semver.Compare(a, b)
+	switch op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "^":
+		return semver.Major(a) == semver.Major(b), nil
+	case "~":
+		return semver.MajorMinor(a) == semver.MajorMinor(b), nil
+	default:
+		return nil, fmt.Errorf("sem_ver: unknown operator %q", op)
+	}
+}
+
+func canonicalSemver(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	if !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+	return s, semver.IsValid(s)
+}
+
+func opStartsWith(args []interface{}, _ interface{}) (interface{}, error) {
+	s, ok1 := arg(args, 0).(string)
+	prefix, ok2 := arg(args, 1).(string)
+	return ok1 && ok2 && strings.HasPrefix(s, prefix), nil
+}
+
+func opEndsWith(args []interface{}, _ interface{}) (interface{}, error) {
+	s, ok1 := arg(args, 0).(string)
+	suffix, ok2 := arg(args, 1).(string)
+	return ok1 && ok2 && strings.HasSuffix(s, suffix), nil
+}
@@ -0,0 +1,144 @@
+package flagd
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFractional(t *testing.T) {
+	// Expected buckets are the ones flagd assigns for the same input.
+	rule := `{"fractional":[{"cat":[{"var":"$flagd.flagKey"},{"var":"email"}]},["red",25],["blue",25],["green",25],["yellow",25]]}`
+
+	for name, test := range map[string]struct {
+		rule string
+		data map[string]interface{}
+		want interface{}
+	}{
+		"rachel": {rule: rule, data: flagdData("headerColor", map[string]interface{}{"email": "rachel@faas.com"}), want: "yellow"},
+		"monica": {rule: rule, data: flagdData("headerColor", map[string]interface{}{"email": "monica@faas.com"}), want: "blue"},
+		"joey":   {rule: rule, data: flagdData("headerColor", map[string]interface{}{"email": "joey@faas.com"}), want: "red"},
+		"ross":   {rule: rule, data: flagdData("headerColor", map[string]interface{}{"email": "ross@faas.com"}), want: "green"},
+		"default bucketing uses targeting key": {
+			rule: `{"fractional":[["red",25],["blue",25],["green",25],["yellow",25]]}`,
+			data: flagdData("headerColor", map[string]interface{}{"targetingKey": "rachel@faas.com"}),
+			want: "yellow",
+		},
+		"missing targeting key": {
+			rule: `{"fractional":[["red",50],["blue",50]]}`,
+			data: flagdData("headerColor", nil),
+			want: nil,
+		},
+		"default weight": {
+			rule: `{"fractional":["anything",["only"]]}`,
+			want: "only",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var r interface{}
+			if err := json.Unmarshal([]byte(test.rule), &r); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := apply(r, test.data)
+			if err != nil {
+				t.Fatalf("apply() error = %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("apply() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFractionalDistribution(t *testing.T) {
+	counts := map[interface{}]int{}
+	for i := 0; i < 10000; i++ {
+		got, err := opFractional([]interface{}{
+			"flag" + strconv.Itoa(i),
+			[]interface{}{"a", 10.0},
+			[]interface{}{"b", 90.0},
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[got]++
+	}
+	if counts["a"] < 700 || counts["a"] > 1300 {
+		t.Errorf("variant a got %d of 10000 evaluations, want about 1000", counts["a"])
+	}
+}
+
+func TestSemVer(t *testing.T) {
+	for name, test := range map[string]struct {
+		args []interface{}
+		want interface{}
+	}{
+		"equal":         {args: []interface{}{"1.2.3", "=", "1.2.3"}, want: true},
+		"not equal":     {args: []interface{}{"1.2.3", "!=", "1.2.4"}, want: true},
+		"less":          {args: []interface{}{"1.2.3", "<", "1.10.0"}, want: true},
+		"greater or eq": {args: []interface{}{"v2.0.0", ">=", "2.0.0"}, want: true},
+		"prerelease":    {args: []interface{}{"1.0.0-beta.1", "<", "1.0.0"}, want: true},
+		"same major":    {args: []interface{}{"1.9.0", "^", "1.2.0"}, want: true},
+		"other major":   {args: []interface{}{"2.0.0", "^", "1.2.0"}, want: false},
+		"same minor":    {args: []interface{}{"1.2.9", "~", "1.2.0"}, want: true},
+		"other minor":   {args: []interface{}{"1.3.0", "~", "1.2.0"}, want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := opSemVer(test.args, nil)
+			if err != nil {
+				t.Fatalf("opSemVer() error = %v", err)
+			}
+			if got != test.want {
+				t.Errorf("opSemVer(%v) = %v, want %v", test.args, got, test.want)
+			}
+		})
+	}
+
+	for name, args := range map[string][]interface{}{
+		"unknown operator":  {"1.0.0", "<>", "1.0.0"},
+		"invalid version":   {"latest", ">", "1.0.0"},
+		"invalid target":    {"1.0.0", ">", "one"},
+		"not a string":      {1.0, "=", "1.0.0"},
+		"missing attribute": {nil, "=", "1.0.0"},
+		"too few arguments": {"1.0.0", "="},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got, err := opSemVer(args, nil); err == nil {
+				t.Errorf("opSemVer(%v) = %v, want an error", args, got)
+			}
+		})
+	}
+}
+
+func TestStartsEndsWith(t *testing.T) {
+	for name, test := range map[string]struct {
+		fn   operator
+		args []interface{}
+		want bool
+	}{
+		"starts_with":        {fn: opStartsWith, args: []interface{}{"dev@example.com", "dev@"}, want: true},
+		"starts_with no":     {fn: opStartsWith, args: []interface{}{"dev@example.com", "ops@"}, want: false},
+		"ends_with":          {fn: opEndsWith, args: []interface{}{"dev@example.com", "@example.com"}, want: true},
+		"ends_with non-text": {fn: opEndsWith, args: []interface{}{42.0, "2"}, want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, _ := test.fn(test.args, nil)
+			if got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func flagdData(flagKey string, ctx map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		flagdProperties: map[string]interface{}{flagKeyProperty: flagKey},
+	}
+	for k, v := range ctx {
+		data[k] = v
+	}
+	return data
+}
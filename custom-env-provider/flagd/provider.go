@@ -0,0 +1,246 @@
+// Package flagd evaluates flag definitions written in flagd's JSON schema locally,
+// without running the flagd daemon. Targeting rules are JSONLogic with flagd's
+// fractional, sem_ver, starts_with and ends_with operations, and resolve to the
+// same variants flagd would pick.
+package flagd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// DefaultEnvVar is the environment variable flag definitions are read from unless
+// WithEnvVar or WithFile is given.
+const DefaultEnvVar = "FLAGD_FLAGS"
+
+type Provider struct {
+	load func() ([]byte, error)
+	now  func() time.Time
+
+	flags atomic.Pointer[flagSet]
+	state atomic.Value // openfeature.State
+}
+
+func NewProvider(opts ...ProviderOption) *Provider {
+	p := &Provider{
+		now: time.Now,
+	}
+	WithEnvVar(DefaultEnvVar)(p)
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	// Definitions that cannot be loaded yet are reported by Init; until then every
+	// flag is reported as not found.
+	_ = p.Reload()
+
+	return p
+}
+
+type ProviderOption func(*Provider)
+
+// WithEnvVar reads flag definitions from the environment variable name.
+func WithEnvVar(name string) ProviderOption {
+	return func(p *Provider) {
+		p.load = func() ([]byte, error) {
+			val, ok := os.LookupEnv(name)
+			if !ok {
+				return nil, fmt.Errorf("%s is not set", name)
+			}
+			return []byte(val), nil
+		}
+	}
+}
+
+// WithFile reads flag definitions from the JSON file at path.
+func WithFile(path string) ProviderOption {
+	return func(p *Provider) {
+		p.load = func() ([]byte, error) {
+			return os.ReadFile(path)
+		}
+	}
+}
+
+// Reload reads and parses the flag definitions again. On error the previously
+// loaded definitions are kept.
+func (p *Provider) Reload() error {
+	b, err := p.load()
+	if err != nil {
+		return err
+	}
+	set, err := parseFlagSet(b)
+	if err != nil {
+		return err
+	}
+	p.flags.Store(set)
+	return nil
+}
+
+var _ openfeature.StateHandler = (*Provider)(nil)
+
+// Init loads the flag definitions. A missing variable or file and an invalid
+// document both fail with PROVIDER_FATAL: the provider has nothing to serve, and
+// nothing short of a corrected deployment gives it anything.
+func (p *Provider) Init(evalCtx openfeature.EvaluationContext) error {
+	if err := p.Reload(); err != nil {
+		p.state.Store(openfeature.FatalState)
+		return &openfeature.ProviderInitError{
+			ErrorCode: openfeature.ProviderFatalCode,
+			Message:   err.Error(),
+		}
+	}
+
+	p.state.Store(openfeature.ReadyState)
+	return nil
+}
+
+func (p *Provider) Shutdown() {
+	p.state.Store(openfeature.NotReadyState)
+}
+
+// Status is FATAL after an Init that could not load the definitions and READY after
+// one that could. Calling Reload does not move it: a reload that fails keeps the
+// definitions already loaded. Before Init and after Shutdown it is NOT_READY.
+func (p *Provider) Status() openfeature.State {
+	if s, ok := p.state.Load().(openfeature.State); ok {
+		return s
+	}
+	return openfeature.NotReadyState
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "flagd-local-evaluator",
+	}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+// resolve evaluates flagKey. When ok is false the caller returns its default value
+// with detail, which carries either an error or the DISABLED reason.
+func (p *Provider) resolve(flagKey string, evalCtx openfeature.FlattenedContext) (value interface{}, detail openfeature.ProviderResolutionDetail, ok bool) {
+	set := p.flags.Load()
+	if set == nil {
+		return nil, errorDetail(openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %s is not defined", flagKey))), false
+	}
+	f, found := set.Flags[flagKey]
+	if !found {
+		return nil, errorDetail(openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %s is not defined", flagKey))), false
+	}
+
+	res, err := f.evaluate(flagKey, evalCtx, p.now())
+	if err != nil {
+		var re openfeature.ResolutionError
+		if !errors.As(err, &re) {
+			re = openfeature.NewGeneralResolutionError(err.Error())
+		}
+		return nil, errorDetail(re), false
+	}
+
+	detail = openfeature.ProviderResolutionDetail{
+		Reason:       res.reason,
+		Variant:      res.variant,
+		FlagMetadata: res.metadata,
+	}
+	return res.value, detail, res.reason != openfeature.DisabledReason
+}
+
+func errorDetail(err openfeature.ResolutionError) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: err,
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
+func typeMismatch(flagKey, variant, want string) openfeature.ProviderResolutionDetail {
+	return errorDetail(openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %s of %s is not %s", variant, flagKey, want)))
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flagKey string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	val, detail, ok := p.resolve(flagKey, evalCtx)
+	if !ok {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	boolVal, ok := val.(bool)
+	if !ok {
+		return openfeature.BoolResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flagKey, detail.Variant, "a boolean"),
+		}
+	}
+	return openfeature.BoolResolutionDetail{Value: boolVal, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flagKey string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	val, detail, ok := p.resolve(flagKey, evalCtx)
+	if !ok {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	strVal, ok := val.(string)
+	if !ok {
+		return openfeature.StringResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flagKey, detail.Variant, "a string"),
+		}
+	}
+	return openfeature.StringResolutionDetail{Value: strVal, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) IntEvaluation(ctx context.Context, flagKey string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	val, detail, ok := p.resolve(flagKey, evalCtx)
+	if !ok {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	f, ok := val.(float64)
+	if !ok || f != float64(int64(f)) {
+		return openfeature.IntResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flagKey, detail.Variant, "an integer"),
+		}
+	}
+	return openfeature.IntResolutionDetail{Value: int64(f), ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flagKey string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	val, detail, ok := p.resolve(flagKey, evalCtx)
+	if !ok {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	f, ok := val.(float64)
+	if !ok {
+		return openfeature.FloatResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: typeMismatch(flagKey, detail.Variant, "a number"),
+		}
+	}
+	return openfeature.FloatResolutionDetail{Value: f, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flagKey string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	val, detail, ok := p.resolve(flagKey, evalCtx)
+	if !ok {
+		return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	switch val.(type) {
+	case map[string]interface{}, []interface{}:
+		return openfeature.InterfaceResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+	}
+	return openfeature.InterfaceResolutionDetail{
+		Value:                    defaultValue,
+		ProviderResolutionDetail: typeMismatch(flagKey, detail.Variant, "an object"),
+	}
+}
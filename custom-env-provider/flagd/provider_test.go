@@ -0,0 +1,321 @@
+package flagd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const testFlags = `{
+  "$schema": "https://flagd.dev/schema/v0/flags.json",
+  "metadata": {"flagSetId": "playground", "version": "1"},
+  "flags": {
+    "new-welcome-banner": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "off",
+      "targeting": {"if": [{"$ref": "is-employee"}, "on"]},
+      "metadata": {"version": "2"}
+    },
+    "headerColor": {
+      "state": "ENABLED",
+      "variants": {"red": "#FF0000", "blue": "#0000FF", "green": "#00FF00", "yellow": "#FFFF00"},
+      "defaultVariant": "red",
+      "targeting": {"fractional": [{"cat": [{"var": "$flagd.flagKey"}, {"var": "email"}]}, ["red", 25], ["blue", 25], ["green", 25], ["yellow", 25]]}
+    },
+    "max-items": {
+      "state": "ENABLED",
+      "variants": {"small": 10, "large": 100},
+      "defaultVariant": "small",
+      "targeting": {"if": [{"sem_ver": [{"var": "appVersion"}, ">=", "2.0.0"]}, "large", null]}
+    },
+    "discount": {
+      "state": "ENABLED",
+      "variants": {"none": 0, "some": 0.15},
+      "defaultVariant": "none",
+      "targeting": {}
+    },
+    "theme": {
+      "state": "ENABLED",
+      "variants": {"light": {"background": "white"}, "dark": {"background": "black"}},
+      "defaultVariant": "light"
+    },
+    "legacy-checkout": {
+      "state": "DISABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "on"
+    },
+    "bool-targeting": {
+      "state": "ENABLED",
+      "variants": {"true": "yes", "false": "no"},
+      "defaultVariant": "false",
+      "targeting": {"==": [{"var": "plan"}, "pro"]}
+    },
+    "broken-targeting": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "off",
+      "targeting": {"var": "variant"}
+    }
+  },
+  "$evaluators": {
+    "is-employee": {"ends_with": [{"var": "email"}, "@example.com"]}
+  }
+}`
+
+func newTestProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, []byte(testFlags), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := NewProvider(WithFile(path))
+	if err := p.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	return p
+}
+
+var cmpResolutionError = cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+	return a.Error() == b.Error()
+})
+
+func TestBooleanEvaluation(t *testing.T) {
+	p := newTestProvider(t)
+
+	for name, test := range map[string]struct {
+		flagKey string
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.BoolResolutionDetail
+	}{
+		"targeting match": {
+			flagKey: "new-welcome-banner",
+			evalCtx: openfeature.FlattenedContext{"email": "dev@example.com"},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{"flagSetId": "playground", "version": "2"},
+				},
+			},
+		},
+		"targeting falls through to default": {
+			flagKey: "new-welcome-banner",
+			evalCtx: openfeature.FlattenedContext{"email": "someone@example.org"},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.DefaultReason,
+					Variant:      "off",
+					FlagMetadata: openfeature.FlagMetadata{"flagSetId": "playground", "version": "2"},
+				},
+			},
+		},
+		"disabled": {
+			flagKey: "legacy-checkout",
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.DisabledReason,
+					FlagMetadata: openfeature.FlagMetadata{"flagSetId": "playground", "version": "1"},
+				},
+			},
+		},
+		"not found": {
+			flagKey: "missing",
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag missing is not defined"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"type mismatch": {
+			flagKey: "headerColor",
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError("variant red of headerColor is not a boolean"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"undefined variant": {
+			flagKey: "broken-targeting",
+			evalCtx: openfeature.FlattenedContext{"variant": "maybe"},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewGeneralResolutionError(`targeting of broken-targeting returned undefined variant "maybe"`),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := p.BooleanEvaluation(context.Background(), test.flagKey, false, test.evalCtx)
+			if diff := cmp.Diff(test.want, got, cmpResolutionError); diff != "" {
+				t.Errorf("BooleanEvaluation() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStringEvaluation(t *testing.T) {
+	p := newTestProvider(t)
+
+	for name, test := range map[string]struct {
+		flagKey     string
+		evalCtx     openfeature.FlattenedContext
+		wantValue   string
+		wantVariant string
+		wantReason  openfeature.Reason
+	}{
+		"fractional": {
+			flagKey:     "headerColor",
+			evalCtx:     openfeature.FlattenedContext{"targetingKey": "user-1", "email": "rachel@faas.com"},
+			wantValue:   "#FFFF00",
+			wantVariant: "yellow",
+			wantReason:  openfeature.TargetingMatchReason,
+		},
+		"fractional other bucket": {
+			flagKey:     "headerColor",
+			evalCtx:     openfeature.FlattenedContext{"targetingKey": "user-1", "email": "monica@faas.com"},
+			wantValue:   "#0000FF",
+			wantVariant: "blue",
+			wantReason:  openfeature.TargetingMatchReason,
+		},
+		"boolean targeting result": {
+			flagKey:     "bool-targeting",
+			evalCtx:     openfeature.FlattenedContext{"plan": "pro"},
+			wantValue:   "yes",
+			wantVariant: "true",
+			wantReason:  openfeature.TargetingMatchReason,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := p.StringEvaluation(context.Background(), test.flagKey, "default", test.evalCtx)
+			if got.Value != test.wantValue || got.Variant != test.wantVariant || got.Reason != test.wantReason {
+				t.Errorf("StringEvaluation() = %q (%s, %s), want %q (%s, %s)", got.Value, got.Variant, got.Reason, test.wantValue, test.wantVariant, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestNumberAndObjectEvaluation(t *testing.T) {
+	p := newTestProvider(t)
+	ctx := context.Background()
+
+	if got := p.IntEvaluation(ctx, "max-items", 0, openfeature.FlattenedContext{"appVersion": "2.1.0"}); got.Value != 100 || got.Reason != openfeature.TargetingMatchReason {
+		t.Errorf("IntEvaluation() = %d (%s), want 100 (%s)", got.Value, got.Reason, openfeature.TargetingMatchReason)
+	}
+	if got := p.IntEvaluation(ctx, "max-items", 0, openfeature.FlattenedContext{"appVersion": "1.9.0"}); got.Value != 10 || got.Reason != openfeature.DefaultReason {
+		t.Errorf("IntEvaluation() = %d (%s), want 10 (%s)", got.Value, got.Reason, openfeature.DefaultReason)
+	}
+	if got := p.FloatEvaluation(ctx, "discount", 1, nil); got.Value != 0 || got.Reason != openfeature.StaticReason {
+		t.Errorf("FloatEvaluation() = %v (%s), want 0 (%s)", got.Value, got.Reason, openfeature.StaticReason)
+	}
+
+	got := p.ObjectEvaluation(ctx, "theme", nil, nil)
+	want := openfeature.InterfaceResolutionDetail{
+		Value: map[string]interface{}{"background": "white"},
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Reason:       openfeature.StaticReason,
+			Variant:      "light",
+			FlagMetadata: openfeature.FlagMetadata{"flagSetId": "playground", "version": "1"},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+		t.Errorf("ObjectEvaluation() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnvVar(t *testing.T) {
+	t.Setenv(DefaultEnvVar, testFlags)
+
+	p := NewProvider()
+	got := p.BooleanEvaluation(context.Background(), "new-welcome-banner", false, openfeature.FlattenedContext{"email": "dev@example.com"})
+	if !got.Value {
+		t.Errorf("BooleanEvaluation() = %+v, want true", got)
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	t.Setenv("FLAGS", `{"flags": {"sale": {
+	  "state": "ENABLED",
+	  "variants": {"on": true, "off": false},
+	  "defaultVariant": "off",
+	  "targeting": {"if": [{">=": [{"var": "$flagd.timestamp"}, 1767225600]}, "on", "off"]}
+	}}}`)
+
+	p := NewProvider(WithEnvVar("FLAGS"))
+	p.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	if got := p.BooleanEvaluation(context.Background(), "sale", false, nil); !got.Value {
+		t.Errorf("BooleanEvaluation() at start of sale = %+v, want true", got)
+	}
+
+	p.now = func() time.Time { return time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC) }
+	if got := p.BooleanEvaluation(context.Background(), "sale", false, nil); got.Value {
+		t.Errorf("BooleanEvaluation() before sale = %+v, want false", got)
+	}
+}
+
+func TestInit(t *testing.T) {
+	for name, test := range map[string]struct {
+		flags   string
+		wantErr bool
+	}{
+		"valid":             {flags: testFlags},
+		"not json":          {flags: `{"flags":`, wantErr: true},
+		"bad state":         {flags: `{"flags":{"a":{"state":"ON","variants":{"on":true},"defaultVariant":"on"}}}`, wantErr: true},
+		"undefined default": {flags: `{"flags":{"a":{"state":"ENABLED","variants":{"on":true},"defaultVariant":"off"}}}`, wantErr: true},
+		"unknown operator":  {flags: `{"flags":{"a":{"state":"ENABLED","variants":{"on":true},"defaultVariant":"on","targeting":{"nope":[]}}}}`, wantErr: true},
+		"undefined $ref":    {flags: `{"flags":{"a":{"state":"ENABLED","variants":{"on":true},"defaultVariant":"on","targeting":{"$ref":"x"}}}}`, wantErr: true},
+		"recursive $ref":    {flags: `{"flags":{"a":{"state":"ENABLED","variants":{"on":true},"defaultVariant":"on","targeting":{"$ref":"x"}}},"$evaluators":{"x":{"!":{"$ref":"x"}}}}`, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("FLAGS", test.flags)
+
+			p := NewProvider(WithEnvVar("FLAGS"))
+			err := p.Init(openfeature.EvaluationContext{})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr {
+				if p.Status() != openfeature.ReadyState {
+					t.Errorf("Status() = %s, want %s", p.Status(), openfeature.ReadyState)
+				}
+				return
+			}
+
+			var initErr *openfeature.ProviderInitError
+			if !errors.As(err, &initErr) || initErr.ErrorCode != openfeature.ProviderFatalCode {
+				t.Errorf("Init() error = %v, want a ProviderInitError with %s", err, openfeature.ProviderFatalCode)
+			}
+			if p.Status() != openfeature.FatalState {
+				t.Errorf("Status() = %s, want %s", p.Status(), openfeature.FatalState)
+			}
+		})
+	}
+}
+
+func TestInitMissingEnvVar(t *testing.T) {
+	p := NewProvider(WithEnvVar("FLAGD_TEST_UNSET"))
+	if err := p.Init(openfeature.EvaluationContext{}); err == nil {
+		t.Error("Init() error = nil, want an error")
+	}
+}
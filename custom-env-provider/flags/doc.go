@@ -0,0 +1,5 @@
+// Package flags provides typed accessors for the flags of this module, generated
+// from flags.json by flaggen. Add or change flags in flags.json and run go generate.
+package flags
+
+//go:generate go run ../cmd/flaggen -manifest flags.json -out flags_gen.go
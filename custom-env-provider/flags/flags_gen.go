@@ -0,0 +1,52 @@
+// Code generated by flaggen. DO NOT EDIT.
+
+package flags
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Flag keys.
+const (
+	CountKey     = "count"
+	MyFeatureKey = "my_feature"
+	NameKey      = "name"
+)
+
+// Count evaluates the count flag, falling back to 0.
+//
+// Number of items shown per page.
+func Count(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) int64 {
+	return client.Int(ctx, CountKey, 0, evalCtx)
+}
+
+// CountDetails evaluates the count flag, returning the full evaluation details.
+func CountDetails(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) (openfeature.IntEvaluationDetails, error) {
+	return client.IntValueDetails(ctx, CountKey, 0, evalCtx)
+}
+
+// MyFeature evaluates the my_feature flag, falling back to false.
+//
+// Enables my feature.
+func MyFeature(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) bool {
+	return client.Boolean(ctx, MyFeatureKey, false, evalCtx)
+}
+
+// MyFeatureDetails evaluates the my_feature flag, returning the full evaluation details.
+func MyFeatureDetails(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) (openfeature.BooleanEvaluationDetails, error) {
+	return client.BooleanValueDetails(ctx, MyFeatureKey, false, evalCtx)
+}
+
+// Name evaluates the name flag, falling back to "".
+//
+// Display name of the service.
+func Name(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) string {
+	return client.String(ctx, NameKey, "", evalCtx)
+}
+
+// NameDetails evaluates the name flag, returning the full evaluation details.
+func NameDetails(ctx context.Context, client openfeature.IClient, evalCtx openfeature.EvaluationContext) (openfeature.StringEvaluationDetails, error) {
+	return client.StringValueDetails(ctx, NameKey, "", evalCtx)
+}
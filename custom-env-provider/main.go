@@ -3,23 +3,27 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/open-feature/go-sdk/openfeature"
 
+	"github.com/knwoop/open-feature-playground/custom-env-provider/flags"
 	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
 )
 
 func main() {
-	pr := provider.NewSimpleEnvProvider()
-	openfeature.SetProvider(pr)
-
-	client := openfeature.NewClient("my-app")
-
 	os.Setenv("FT_MY_FEATURE", "true")
 	os.Setenv("FT_COUNT", "42")
 	os.Setenv("FT_NAME", "test")
 
+	pr := provider.NewSimpleEnvProvider()
+	if err := openfeature.SetProviderAndWait(pr); err != nil {
+		log.Fatal(err)
+	}
+
+	client := openfeature.NewClient("my-app")
+
 	ctx := context.Background()
 
 	evalCtx := openfeature.NewEvaluationContext(
@@ -27,23 +31,23 @@ func main() {
 		map[string]interface{}{},
 	)
 
-	boolValue, _ := client.BooleanValue(ctx, "my_feature", false, evalCtx)
-	intValue, _ := client.IntValue(ctx, "count", 0, evalCtx)
-	stringValue, _ := client.StringValue(ctx, "name", "", evalCtx)
+	boolValue := flags.MyFeature(ctx, client, evalCtx)
+	intValue := flags.Count(ctx, client, evalCtx)
+	stringValue := flags.Name(ctx, client, evalCtx)
 
 	fmt.Println(boolValue, intValue, stringValue)
 
 	evalCtx = openfeature.NewEvaluationContext(
 		"user-123", // targetingKey
 		map[string]interface{}{ // attributes
-			"my_feature": false,
-			"count":      1000,
+			flags.MyFeatureKey: false,
+			flags.CountKey:     1000,
 		},
 	)
 
-	evaluatedBoolValue, _ := client.BooleanValue(ctx, "my_feature", false, evalCtx)
-	evaluatedIntValue, _ := client.IntValue(ctx, "count", 0, evalCtx)
-	evaluatedStringValue, _ := client.StringValue(ctx, "name", "", evalCtx)
+	evaluatedBoolValue := flags.MyFeature(ctx, client, evalCtx)
+	evaluatedIntValue := flags.Count(ctx, client, evalCtx)
+	evaluatedStringValue := flags.Name(ctx, client, evalCtx)
 
 	fmt.Println(evaluatedBoolValue, evaluatedIntValue, evaluatedStringValue)
 }
@@ -0,0 +1,53 @@
+// Package ofrep implements the OpenFeature Remote Evaluation Protocol (OFREP):
+// an http.Handler that serves any OpenFeature provider over it, and a provider
+// that evaluates flags against a remote OFREP endpoint.
+//
+// See https://openfeature.dev/specification/appendix-c for the protocol.
+package ofrep
+
+import (
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	// EvaluatePath is the path prefix of single flag evaluation; the flag key follows it.
+	EvaluatePath = "/ofrep/v1/evaluate/flags/"
+	// BulkEvaluatePath is the path of bulk evaluation.
+	BulkEvaluatePath = "/ofrep/v1/evaluate/flags"
+)
+
+// evaluationRequest is the body of both single and bulk evaluation requests.
+type evaluationRequest struct {
+	Context openfeature.FlattenedContext `json:"context"`
+}
+
+// evaluationResult is a successful evaluation of one flag.
+type evaluationResult struct {
+	Key      string                   `json:"key"`
+	Value    interface{}              `json:"value"`
+	Reason   openfeature.Reason       `json:"reason,omitempty"`
+	Variant  string                   `json:"variant,omitempty"`
+	Metadata openfeature.FlagMetadata `json:"metadata,omitempty"`
+}
+
+// evaluationError is a failed evaluation of one flag, or with no key a failed request.
+type evaluationError struct {
+	Key          string                `json:"key,omitempty"`
+	ErrorCode    openfeature.ErrorCode `json:"errorCode,omitempty"`
+	ErrorDetails string                `json:"errorDetails,omitempty"`
+}
+
+// bulkFlag is one flag of a bulk evaluation response; it is either a result or an error.
+type bulkFlag struct {
+	Key          string                   `json:"key"`
+	Value        interface{}              `json:"value,omitempty"`
+	Reason       openfeature.Reason       `json:"reason,omitempty"`
+	Variant      string                   `json:"variant,omitempty"`
+	Metadata     openfeature.FlagMetadata `json:"metadata,omitempty"`
+	ErrorCode    openfeature.ErrorCode    `json:"errorCode,omitempty"`
+	ErrorDetails string                   `json:"errorDetails,omitempty"`
+}
+
+type bulkEvaluationResponse struct {
+	Flags []bulkFlag `json:"flags"`
+}
@@ -0,0 +1,409 @@
+package ofrep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+const (
+	// DefaultRetryAfter is how long a Provider backs off after a 429 response that
+	// does not say how long to wait.
+	DefaultRetryAfter = time.Second
+	// maxCacheEntries bounds the bulk evaluations a Provider caches, one per distinct
+	// evaluation context.
+	maxCacheEntries = 1024
+)
+
+// Provider evaluates flags against a remote OFREP endpoint.
+//
+// By default every evaluation is a request to the endpoint. With WithBulkCache the
+// provider instead fetches all flags for an evaluation context at once and serves
+// evaluations from that result until it expires, revalidating it with its ETag.
+//
+// A 429 Too Many Requests response makes the provider back off for the duration of
+// its Retry-After header: meanwhile evaluations are served from the bulk cache, even
+// if expired, or fail with a GENERAL error without contacting the endpoint. An
+// expired bulk evaluation is likewise served when revalidating it fails.
+type Provider struct {
+	baseURL  string
+	client   *http.Client
+	header   http.Header
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu           sync.Mutex
+	backoffUntil time.Time
+	cache        map[string]*bulkEntry
+}
+
+// bulkEntry is a cached bulk evaluation for one evaluation context.
+type bulkEntry struct {
+	etag      string
+	fetchedAt time.Time
+	flags     map[string]bulkFlag
+}
+
+type ProviderOption func(*Provider)
+
+// NewProvider returns a provider for the OFREP endpoint at baseURL, e.g.
+// "https://flags.example.com"; the /ofrep/v1 paths are appended to it.
+func NewProvider(baseURL string, opts ...ProviderOption) *Provider {
+	p := &Provider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  http.DefaultClient,
+		header:  http.Header{},
+		now:     time.Now,
+		cache:   make(map[string]*bulkEntry),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithHTTPClient sets the client requests are sent with.
+func WithHTTPClient(c *http.Client) ProviderOption {
+	return func(p *Provider) {
+		p.client = c
+	}
+}
+
+// WithHeader adds a header to every request, e.g. for authorization.
+func WithHeader(key, value string) ProviderOption {
+	return func(p *Provider) {
+		p.header.Add(key, value)
+	}
+}
+
+// WithBulkCache serves evaluations from bulk evaluations cached for ttl. A zero or
+// negative ttl disables the cache.
+func WithBulkCache(ttl time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.cacheTTL = ttl
+	}
+}
+
+// WithClock sets the function the provider reads the current time from, for the
+// Retry-After backoff and the bulk cache. It defaults to time.Now.
+func WithClock(now func() time.Time) ProviderOption {
+	return func(p *Provider) {
+		p.now = now
+	}
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "ofrep",
+	}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flagKey string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	val, detail := evaluate(p, ctx, flagKey, defaultValue, evalCtx, func(v interface{}) (bool, bool) {
+		b, ok := v.(bool)
+		return b, ok
+	})
+	return openfeature.BoolResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flagKey string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	val, detail := evaluate(p, ctx, flagKey, defaultValue, evalCtx, func(v interface{}) (string, bool) {
+		s, ok := v.(string)
+		return s, ok
+	})
+	return openfeature.StringResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) IntEvaluation(ctx context.Context, flagKey string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	val, detail := evaluate(p, ctx, flagKey, defaultValue, evalCtx, func(v interface{}) (int64, bool) {
+		i, ok := v.(int64)
+		return i, ok
+	})
+	return openfeature.IntResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flagKey string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	val, detail := evaluate(p, ctx, flagKey, defaultValue, evalCtx, func(v interface{}) (float64, bool) {
+		switch n := v.(type) {
+		case float64:
+			return n, true
+		case int64:
+			return float64(n), true
+		}
+		return 0, false
+	})
+	return openfeature.FloatResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flagKey string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	val, detail := evaluate(p, ctx, flagKey, defaultValue, evalCtx, func(v interface{}) (interface{}, bool) {
+		return v, true
+	})
+	return openfeature.InterfaceResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+// evaluate resolves flagKey and converts its value with convert, serving
+// defaultValue on any error.
+func evaluate[T any](p *Provider, ctx context.Context, flagKey string, defaultValue T, evalCtx openfeature.FlattenedContext, convert func(interface{}) (T, bool)) (T, openfeature.ProviderResolutionDetail) {
+	var (
+		flag bulkFlag
+		err  error
+	)
+	if p.cacheTTL > 0 {
+		flag, err = p.fromBulk(ctx, flagKey, evalCtx)
+	} else {
+		flag, err = p.fetchFlag(ctx, flagKey, evalCtx)
+	}
+	if err != nil {
+		return defaultValue, errorDetail(openfeature.NewGeneralResolutionError(err.Error()))
+	}
+	if flag.ErrorCode != "" {
+		return defaultValue, errorDetail(resolutionError(flag.ErrorCode, flag.ErrorDetails))
+	}
+
+	val, ok := convert(flag.Value)
+	if !ok {
+		return defaultValue, errorDetail(openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("flag %s is %T", flagKey, flag.Value)))
+	}
+	return val, openfeature.ProviderResolutionDetail{
+		Reason:       flag.Reason,
+		Variant:      flag.Variant,
+		FlagMetadata: flag.Metadata,
+	}
+}
+
+func errorDetail(err openfeature.ResolutionError) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: err,
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
+// resolutionError maps an OFREP error code to the SDK's resolution error.
+func resolutionError(code openfeature.ErrorCode, details string) openfeature.ResolutionError {
+	switch code {
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(details)
+	case openfeature.ParseErrorCode:
+		return openfeature.NewParseErrorResolutionError(details)
+	case openfeature.TypeMismatchCode:
+		return openfeature.NewTypeMismatchResolutionError(details)
+	case openfeature.TargetingKeyMissingCode:
+		return openfeature.NewTargetingKeyMissingResolutionError(details)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(details)
+	case openfeature.ProviderNotReadyCode:
+		return openfeature.NewProviderNotReadyResolutionError(details)
+	default:
+		return openfeature.NewGeneralResolutionError(details)
+	}
+}
+
+// fetchFlag evaluates a single flag on the endpoint.
+func (p *Provider) fetchFlag(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (bulkFlag, error) {
+	resp, err := p.post(ctx, EvaluatePath+url.PathEscape(flagKey), evalCtx, "")
+	if err != nil {
+		return bulkFlag{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusBadRequest, http.StatusNotFound:
+	default:
+		return bulkFlag{}, unexpectedStatus(resp)
+	}
+
+	var flag bulkFlag
+	if err := decode(resp, &flag); err != nil {
+		return bulkFlag{}, err
+	}
+	normalizeFlag(&flag)
+	if resp.StatusCode != http.StatusOK && flag.ErrorCode == "" {
+		flag.ErrorCode = openfeature.GeneralCode
+	}
+	return flag, nil
+}
+
+// fromBulk serves flagKey from the cached bulk evaluation of evalCtx, fetching or
+// revalidating it first if it has expired.
+func (p *Provider) fromBulk(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (bulkFlag, error) {
+	key, err := json.Marshal(evalCtx)
+	if err != nil {
+		return bulkFlag{}, fmt.Errorf("encode evaluation context: %w", err)
+	}
+
+	p.mu.Lock()
+	entry := p.cache[string(key)]
+	p.mu.Unlock()
+
+	if entry == nil || p.now().Sub(entry.fetchedAt) >= p.cacheTTL {
+		fresh, err := p.fetchBulk(ctx, evalCtx, entry)
+		switch {
+		case err == nil:
+			entry = fresh
+			p.store(string(key), entry)
+		case entry == nil:
+			return bulkFlag{}, err
+		}
+		// An expired entry is still better than nothing while the endpoint is
+		// unavailable.
+	}
+
+	flag, ok := entry.flags[flagKey]
+	if !ok {
+		return bulkFlag{Key: flagKey, ErrorCode: openfeature.FlagNotFoundCode, ErrorDetails: fmt.Sprintf("flag %s not found", flagKey)}, nil
+	}
+	return flag, nil
+}
+
+// fetchBulk evaluates every flag on the endpoint. If prev is not nil it is
+// revalidated, and returned with a new fetch time when it has not changed.
+func (p *Provider) fetchBulk(ctx context.Context, evalCtx openfeature.FlattenedContext, prev *bulkEntry) (*bulkEntry, error) {
+	var etag string
+	if prev != nil {
+		etag = prev.etag
+	}
+	resp, err := p.post(ctx, BulkEvaluatePath, evalCtx, etag)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	now := p.now()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if prev != nil {
+			return &bulkEntry{etag: prev.etag, fetchedAt: now, flags: prev.flags}, nil
+		}
+		return nil, unexpectedStatus(resp)
+	default:
+		return nil, unexpectedStatus(resp)
+	}
+
+	var body bulkEvaluationResponse
+	if err := decode(resp, &body); err != nil {
+		return nil, err
+	}
+	entry := &bulkEntry{
+		etag:      resp.Header.Get("ETag"),
+		fetchedAt: now,
+		flags:     make(map[string]bulkFlag, len(body.Flags)),
+	}
+	for _, flag := range body.Flags {
+		normalizeFlag(&flag)
+		entry.flags[flag.Key] = flag
+	}
+	return entry, nil
+}
+
+func (p *Provider) store(key string, entry *bulkEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.cache[key]; !ok && len(p.cache) >= maxCacheEntries {
+		var oldest string
+		for k, e := range p.cache {
+			if oldest == "" || e.fetchedAt.Before(p.cache[oldest].fetchedAt) {
+				oldest = k
+			}
+		}
+		delete(p.cache, oldest)
+	}
+	p.cache[key] = entry
+}
+
+// post sends an evaluation request unless the provider is backing off, and starts
+// backing off when the endpoint answers 429.
+func (p *Provider) post(ctx context.Context, path string, evalCtx openfeature.FlattenedContext, etag string) (*http.Response, error) {
+	p.mu.Lock()
+	until := p.backoffUntil
+	p.mu.Unlock()
+	if p.now().Before(until) {
+		return nil, fmt.Errorf("rate limited until %s", until.Format(time.RFC3339))
+	}
+
+	body, err := json.Marshal(evaluationRequest{Context: evalCtx})
+	if err != nil {
+		return nil, fmt.Errorf("encode evaluation context: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		until := p.now().Add(retryAfter(resp.Header.Get("Retry-After"), p.now()))
+		p.mu.Lock()
+		if until.After(p.backoffUntil) {
+			p.backoffUntil = until
+		}
+		p.mu.Unlock()
+		return nil, fmt.Errorf("rate limited until %s", until.Format(time.RFC3339))
+	}
+	return resp, nil
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return t.Sub(now)
+	}
+	return DefaultRetryAfter
+}
+
+func decode(resp *http.Response, v interface{}) error {
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// normalizeFlag converts the json.Numbers of a decoded flag, so that cached flags
+// are never modified once stored.
+func normalizeFlag(f *bulkFlag) {
+	f.Value = provider.NormalizeNumbers(f.Value)
+	if f.Metadata != nil {
+		provider.NormalizeNumbers(map[string]interface{}(f.Metadata))
+	}
+}
+
+func unexpectedStatus(resp *http.Response) error {
+	var body evaluationError
+	if decode(resp, &body) == nil && body.ErrorDetails != "" {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, body.ErrorDetails)
+	}
+	return fmt.Errorf("unexpected status %s", resp.Status)
+}
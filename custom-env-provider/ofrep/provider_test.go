@@ -0,0 +1,300 @@
+package ofrep
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+// recorder records the response statuses of a handler.
+type recorder struct {
+	handler http.Handler
+
+	mu       sync.Mutex
+	statuses []int
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	rec.handler.ServeHTTP(sw, r)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.statuses = append(rec.statuses, sw.status)
+}
+
+func (rec *recorder) Statuses() []int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]int(nil), rec.statuses...)
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func newRemote(t *testing.T, source provider.MapSource) (*httptest.Server, *recorder) {
+	t.Helper()
+
+	rec := &recorder{handler: NewHandler(provider.NewSimpleEnvProvider(provider.WithSource(source)))}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+	return srv, rec
+}
+
+var remoteFlags = provider.MapSource{
+	"FT_ENABLED": "true",
+	"FT_LIMIT":   "10",
+	"FT_RATIO":   "0.5",
+	"FT_NAME":    "checkout",
+	"FT_ONE":     "1",
+	"FT_COLORS":  `["red","blue"]`,
+	"FT_TIERED":  `{"variants":{"free":1,"paid":2},"defaultVariant":"free","rules":[{"if":{"attribute":"plan","op":"eq","value":"paid"},"variant":"paid"}]}`,
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]ProviderOption{
+		"single":     nil,
+		"bulk cache": {WithBulkCache(time.Minute)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv, _ := newRemote(t, remoteFlags)
+			p := NewProvider(srv.URL, opts...)
+			ctx := context.Background()
+			evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1", "plan": "paid"}
+			ignore := cmpopts.IgnoreUnexported(openfeature.ResolutionError{})
+
+			if diff := cmp.Diff(openfeature.BoolResolutionDetail{
+				Value:                    true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: provider.ReasonEnv},
+			}, p.BooleanEvaluation(ctx, "enabled", false, evalCtx), ignore); diff != "" {
+				t.Errorf("BooleanEvaluation() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(openfeature.StringResolutionDetail{
+				Value:                    "checkout",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: provider.ReasonEnv},
+			}, p.StringEvaluation(ctx, "name", "", evalCtx), ignore); diff != "" {
+				t.Errorf("StringEvaluation() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(openfeature.IntResolutionDetail{
+				Value:                    10,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: provider.ReasonEnv},
+			}, p.IntEvaluation(ctx, "limit", 0, evalCtx), ignore); diff != "" {
+				t.Errorf("IntEvaluation() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(openfeature.FloatResolutionDetail{
+				Value:                    0.5,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: provider.ReasonEnv},
+			}, p.FloatEvaluation(ctx, "ratio", 0, evalCtx), ignore); diff != "" {
+				t.Errorf("FloatEvaluation() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(openfeature.InterfaceResolutionDetail{
+				Value:                    []interface{}{"red", "blue"},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: provider.ReasonEnv},
+			}, p.ObjectEvaluation(ctx, "colors", nil, evalCtx), ignore); diff != "" {
+				t.Errorf("ObjectEvaluation() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(openfeature.IntResolutionDetail{
+				Value: 2,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "paid",
+					FlagMetadata: openfeature.FlagMetadata{"ruleIndex": int64(0)},
+				},
+			}, p.IntEvaluation(ctx, "tiered", 0, evalCtx), ignore); diff != "" {
+				t.Errorf("IntEvaluation() of a targeted flag mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProviderErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]ProviderOption{
+		"single":     nil,
+		"bulk cache": {WithBulkCache(time.Minute)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv, _ := newRemote(t, remoteFlags)
+			p := NewProvider(srv.URL, opts...)
+			ctx := context.Background()
+
+			got := p.IntEvaluation(ctx, "missing", 7, openfeature.FlattenedContext{})
+			if got.Value != 7 || got.ResolutionDetail().ErrorCode != openfeature.FlagNotFoundCode {
+				t.Errorf("IntEvaluation() of a missing flag = %d, %s; want 7, %s", got.Value, got.ResolutionDetail().ErrorCode, openfeature.FlagNotFoundCode)
+			}
+
+			got = p.IntEvaluation(ctx, "name", 7, openfeature.FlattenedContext{})
+			if got.Value != 7 || got.ResolutionDetail().ErrorCode != openfeature.TypeMismatchCode {
+				t.Errorf("IntEvaluation() of a string flag = %d, %s; want 7, %s", got.Value, got.ResolutionDetail().ErrorCode, openfeature.TypeMismatchCode)
+			}
+
+			// The server serves "1" as a number, so it is not a boolean here.
+			b := p.BooleanEvaluation(ctx, "one", false, openfeature.FlattenedContext{})
+			if b.Value || b.ResolutionDetail().ErrorCode != openfeature.TypeMismatchCode {
+				t.Errorf("BooleanEvaluation() of a numeric flag = %v, %s; want false, %s", b.Value, b.ResolutionDetail().ErrorCode, openfeature.TypeMismatchCode)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		got := NewProvider(srv.URL).BooleanEvaluation(context.Background(), "enabled", true, openfeature.FlattenedContext{})
+		if !got.Value || got.ResolutionDetail().ErrorCode != openfeature.GeneralCode {
+			t.Errorf("BooleanEvaluation() = %v, %s; want true, %s", got.Value, got.ResolutionDetail().ErrorCode, openfeature.GeneralCode)
+		}
+	})
+}
+
+func TestProviderBulkCache(t *testing.T) {
+	t.Parallel()
+
+	srv, rec := newRemote(t, remoteFlags)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProvider(srv.URL, WithBulkCache(time.Minute), WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+	user1 := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}
+
+	p.BooleanEvaluation(ctx, "enabled", false, user1)
+	p.IntEvaluation(ctx, "limit", 0, user1)
+	p.StringEvaluation(ctx, "name", "", user1)
+	if diff := cmp.Diff([]int{http.StatusOK}, rec.Statuses()); diff != "" {
+		t.Errorf("requests within the ttl mismatch (-want +got):\n%s", diff)
+	}
+
+	p.BooleanEvaluation(ctx, "enabled", false, openfeature.FlattenedContext{openfeature.TargetingKey: "user-2"})
+	if diff := cmp.Diff([]int{http.StatusOK, http.StatusOK}, rec.Statuses()); diff != "" {
+		t.Errorf("requests for another context mismatch (-want +got):\n%s", diff)
+	}
+
+	now = now.Add(time.Minute)
+	if got := p.BooleanEvaluation(ctx, "enabled", false, user1); !got.Value {
+		t.Errorf("BooleanEvaluation() after revalidation = %v, want true", got.Value)
+	}
+	if diff := cmp.Diff([]int{http.StatusOK, http.StatusOK, http.StatusNotModified}, rec.Statuses()); diff != "" {
+		t.Errorf("requests after the ttl mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProviderRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		limited = true
+	)
+	remote := NewHandler(provider.NewSimpleEnvProvider(provider.WithSource(remoteFlags)))
+	rec := &recorder{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if limited {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		remote.ServeHTTP(w, r)
+	})}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProvider(srv.URL, WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	for range 3 {
+		got := p.BooleanEvaluation(ctx, "enabled", false, openfeature.FlattenedContext{})
+		if got.Value || got.ResolutionDetail().ErrorCode != openfeature.GeneralCode {
+			t.Errorf("BooleanEvaluation() while rate limited = %v, %s; want false, %s", got.Value, got.ResolutionDetail().ErrorCode, openfeature.GeneralCode)
+		}
+	}
+	if diff := cmp.Diff([]int{http.StatusTooManyRequests}, rec.Statuses()); diff != "" {
+		t.Errorf("requests while backing off mismatch (-want +got):\n%s", diff)
+	}
+
+	mu.Lock()
+	limited = false
+	mu.Unlock()
+	now = now.Add(30 * time.Second)
+
+	if got := p.BooleanEvaluation(ctx, "enabled", false, openfeature.FlattenedContext{}); !got.Value {
+		t.Errorf("BooleanEvaluation() after backing off = %v, want true", got.Value)
+	}
+}
+
+func TestProviderRetryAfterServesStaleCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		limited bool
+	)
+	remote := NewHandler(provider.NewSimpleEnvProvider(provider.WithSource(remoteFlags)))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if limited {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		remote.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProvider(srv.URL, WithBulkCache(time.Second), WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	p.BooleanEvaluation(ctx, "enabled", false, openfeature.FlattenedContext{})
+
+	mu.Lock()
+	limited = true
+	mu.Unlock()
+	now = now.Add(time.Minute)
+
+	got := p.BooleanEvaluation(ctx, "enabled", false, openfeature.FlattenedContext{})
+	if !got.Value || got.Error() != nil {
+		t.Errorf("BooleanEvaluation() = %v, %v; want the stale cached true", got.Value, got.Error())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for header, want := range map[string]time.Duration{
+		"":                              DefaultRetryAfter,
+		"120":                           2 * time.Minute,
+		"soon":                          DefaultRetryAfter,
+		"Mon, 01 Jan 2024 00:00:10 GMT": 10 * time.Second,
+	} {
+		if got := retryAfter(header, now); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
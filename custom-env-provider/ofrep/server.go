@@ -0,0 +1,199 @@
+package ofrep
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+)
+
+// maxRequestSize bounds the evaluation context a client may send.
+const maxRequestSize = 1 << 20
+
+// FlagLister is implemented by providers that can list the flags they serve, such
+// as provider.SimpleEnvProvider. Bulk evaluation needs it.
+type FlagLister interface {
+	FlagKeys() []string
+}
+
+// Handler serves OFREP single and bulk flag evaluation from an OpenFeature provider.
+//
+// OFREP requests carry no type information, so every flag is evaluated as the
+// first type that serves it, in the order of provider.AutoTypes: integer, float,
+// boolean, object, string. A flag set to 1 or 0 is therefore served as a number
+// and a flag such as 02134 as the integer 2134, which a client evaluating it as
+// a boolean or string reports as TYPE_MISMATCH. Flags meant as booleans should
+// be set to true or false, and strings that look like numbers should be served
+// from a flag document whose variants are JSON strings.
+type Handler struct {
+	provider openfeature.FeatureProvider
+	mux      *http.ServeMux
+}
+
+// NewHandler returns a handler serving p. Bulk evaluation is only available when p
+// implements FlagLister; otherwise it answers 501 Not Implemented.
+func NewHandler(p openfeature.FeatureProvider) *Handler {
+	h := &Handler{
+		provider: p,
+		mux:      http.NewServeMux(),
+	}
+	h.mux.HandleFunc("POST "+EvaluatePath+"{key}", h.evaluate)
+	h.mux.HandleFunc("POST "+BulkEvaluatePath, h.evaluateBulk)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) evaluate(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+
+	evalCtx, err := readContext(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, evaluationError{
+			Key:          key,
+			ErrorCode:    openfeature.InvalidContextCode,
+			ErrorDetails: err.Error(),
+		})
+		return
+	}
+
+	flag := h.resolve(r.Context(), key, evalCtx)
+	switch {
+	case flag.ErrorCode == openfeature.FlagNotFoundCode:
+		writeJSON(w, http.StatusNotFound, evaluationError{Key: key, ErrorCode: flag.ErrorCode, ErrorDetails: flag.ErrorDetails})
+	case flag.ErrorCode != "":
+		writeJSON(w, http.StatusBadRequest, evaluationError{Key: key, ErrorCode: flag.ErrorCode, ErrorDetails: flag.ErrorDetails})
+	default:
+		writeJSON(w, http.StatusOK, evaluationResult{
+			Key:      key,
+			Value:    flag.Value,
+			Reason:   flag.Reason,
+			Variant:  flag.Variant,
+			Metadata: flag.Metadata,
+		})
+	}
+}
+
+// evaluateBulk evaluates every flag of the provider. The response carries an ETag
+// so that clients can poll with If-None-Match and get 304 Not Modified while
+// nothing they would see has changed.
+func (h *Handler) evaluateBulk(w http.ResponseWriter, r *http.Request) {
+	lister, ok := h.provider.(FlagLister)
+	if !ok {
+		writeJSON(w, http.StatusNotImplemented, evaluationError{
+			ErrorCode:    openfeature.GeneralCode,
+			ErrorDetails: "provider cannot list its flags",
+		})
+		return
+	}
+
+	evalCtx, err := readContext(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, evaluationError{
+			ErrorCode:    openfeature.InvalidContextCode,
+			ErrorDetails: err.Error(),
+		})
+		return
+	}
+
+	resp := bulkEvaluationResponse{Flags: []bulkFlag{}}
+	for _, key := range lister.FlagKeys() {
+		resp.Flags = append(resp.Flags, h.resolve(r.Context(), key, evalCtx))
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, evaluationError{ErrorDetails: err.Error()})
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// resolve evaluates key as the first type the provider serves it as.
+func (h *Handler) resolve(ctx context.Context, key string, evalCtx openfeature.FlattenedContext) bulkFlag {
+	res := provider.EvaluateAuto(ctx, h.provider, key, evalCtx)
+	if rd := res.ResolutionDetail(); rd.ErrorCode != "" {
+		return bulkFlag{Key: key, ErrorCode: rd.ErrorCode, ErrorDetails: rd.ErrorMessage}
+	}
+	return bulkFlag{
+		Key:      key,
+		Value:    res.Value,
+		Reason:   res.Reason,
+		Variant:  res.Variant,
+		Metadata: res.FlagMetadata,
+	}
+}
+
+// readContext decodes the evaluation context of a request. An empty body is an
+// empty context.
+func readContext(r *http.Request) (openfeature.FlattenedContext, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxRequestSize {
+		return nil, fmt.Errorf("request body exceeds %d bytes", maxRequestSize)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return openfeature.FlattenedContext{}, nil
+	}
+
+	var req struct {
+		Context json.RawMessage `json:"context"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	if req.Context == nil {
+		return openfeature.FlattenedContext{}, nil
+	}
+	evalCtx, err := provider.DecodeContext(bytes.NewReader(req.Context))
+	if err != nil {
+		return nil, fmt.Errorf("invalid context: %w", err)
+	}
+	if tk, ok := evalCtx[openfeature.TargetingKey]; ok {
+		if _, ok := tk.(string); !ok {
+			return nil, errors.New("targetingKey must be a string")
+		}
+	}
+	return evalCtx, nil
+}
+
+// etagMatches reports whether an If-None-Match header lists etag.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
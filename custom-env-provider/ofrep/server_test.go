@@ -0,0 +1,201 @@
+package ofrep
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knwoop/open-feature-playground/custom-env-provider/provider"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+)
+
+func newTestServer(t *testing.T, source provider.MapSource) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(NewHandler(provider.NewSimpleEnvProvider(provider.WithSource(source))))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func post(t *testing.T, url, body string, header http.Header) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var got map[string]interface{}
+	if resp.StatusCode != http.StatusNotModified {
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return resp, got
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, provider.MapSource{
+		"FT_ENABLED": "true",
+		"FT_LIMIT":   "10",
+		"FT_RATIO":   "0.5",
+		"FT_COLORS":  `["red","blue"]`,
+		"FT_NAME":    "checkout",
+		"FT_TIERED":  `{"variants":{"free":1,"paid":2},"defaultVariant":"free","rules":[{"if":{"attribute":"plan","op":"eq","value":"paid"},"variant":"paid"}]}`,
+		"FT_ONE":     "1",
+		"FT_ZIP":     "02134",
+		"FT_CODE":    `{"variants":{"zip":"02134"},"defaultVariant":"zip"}`,
+	})
+
+	for name, test := range map[string]struct {
+		flagKey    string
+		body       string
+		wantStatus int
+		want       map[string]interface{}
+	}{
+		"boolean": {
+			flagKey:    "enabled",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "enabled", "value": true, "reason": provider.ReasonEnv},
+		},
+		"integer": {
+			flagKey:    "limit",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "limit", "value": float64(10), "reason": provider.ReasonEnv},
+		},
+		"float": {
+			flagKey:    "ratio",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "ratio", "value": 0.5, "reason": provider.ReasonEnv},
+		},
+		"object": {
+			flagKey:    "colors",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "colors", "value": []interface{}{"red", "blue"}, "reason": provider.ReasonEnv},
+		},
+		"string": {
+			flagKey:    "name",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "name", "value": "checkout", "reason": provider.ReasonEnv},
+		},
+		"one before boolean": {
+			flagKey:    "one",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "one", "value": float64(1), "reason": provider.ReasonEnv},
+		},
+		"numeric string before string": {
+			flagKey:    "zip",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "zip", "value": float64(2134), "reason": provider.ReasonEnv},
+		},
+		"string variant": {
+			flagKey:    "code",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "code", "value": "02134", "reason": "DEFAULT", "variant": "zip"},
+		},
+		"targeting from context": {
+			flagKey:    "tiered",
+			body:       `{"context":{"targetingKey":"user-1","plan":"paid"}}`,
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"key": "tiered", "value": float64(2), "reason": "TARGETING_MATCH", "variant": "paid", "metadata": map[string]interface{}{"ruleIndex": float64(0)}},
+		},
+		"not found": {
+			flagKey:    "missing",
+			wantStatus: http.StatusNotFound,
+			want:       map[string]interface{}{"key": "missing", "errorCode": "FLAG_NOT_FOUND", "errorDetails": "flag FT_MISSING is not set"},
+		},
+		"invalid body": {
+			flagKey:    "enabled",
+			body:       `{"context":`,
+			wantStatus: http.StatusBadRequest,
+			want:       map[string]interface{}{"key": "enabled", "errorCode": "INVALID_CONTEXT", "errorDetails": "invalid request body: unexpected EOF"},
+		},
+		"targeting key not a string": {
+			flagKey:    "enabled",
+			body:       `{"context":{"targetingKey":1}}`,
+			wantStatus: http.StatusBadRequest,
+			want:       map[string]interface{}{"key": "enabled", "errorCode": "INVALID_CONTEXT", "errorDetails": "targetingKey must be a string"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, got := post(t, srv.URL+EvaluatePath+test.flagKey, test.body, nil)
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("response mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateBulk(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, provider.MapSource{
+		"FT_ENABLED": "true",
+		"FT_LIMIT":   "10",
+		"FT_BROKEN":  `{"variants":{"on":true},"defaultVariant":"on","rules":[{"if":{"attribute":"plan","op":"eq","value":"paid"},"variant":"unknown"}]}`,
+	})
+
+	resp, got := post(t, srv.URL+BulkEvaluatePath, `{"context":{"targetingKey":"user-1","plan":"paid"}}`, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	want := map[string]interface{}{
+		"flags": []interface{}{
+			map[string]interface{}{"key": "broken", "errorCode": "PARSE_ERROR", "errorDetails": `variant "unknown" is not defined`},
+			map[string]interface{}{"key": "enabled", "value": true, "reason": provider.ReasonEnv},
+			map[string]interface{}{"key": "limit", "value": float64(10), "reason": provider.ReasonEnv},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("response mismatch (-want +got):\n%s", diff)
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+
+	resp, _ = post(t, srv.URL+BulkEvaluatePath, `{"context":{"targetingKey":"user-1","plan":"paid"}}`, http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("status with matching If-None-Match = %d, want %d", resp.StatusCode, http.StatusNotModified)
+	}
+
+	resp, _ = post(t, srv.URL+BulkEvaluatePath, `{"context":{"targetingKey":"user-1","plan":"paid","enabled":false}}`, http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status after the evaluation changed = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if resp.Header.Get("ETag") == etag {
+		t.Error("ETag did not change with the evaluation")
+	}
+}
+
+func TestEvaluateBulkUnlisted(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(NewHandler(memprovider.NewInMemoryProvider(nil)))
+	t.Cleanup(srv.Close)
+
+	resp, _ := post(t, srv.URL+BulkEvaluatePath, "", nil)
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotImplemented)
+	}
+}
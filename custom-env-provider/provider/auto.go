@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// autoTypes are the types Evaluate accepts, in the order EvaluateAuto tries them.
+// Numbers come before booleans because "1" and "0" also parse as booleans.
+var autoTypes = []string{"int", "float", "bool", "object", "string"}
+
+// AutoTypes returns the types Evaluate accepts, in the order EvaluateAuto tries them.
+func AutoTypes() []string {
+	return append([]string(nil), autoTypes...)
+}
+
+// Resolution is a flag evaluated as one of the AutoTypes.
+type Resolution struct {
+	Type  string
+	Value interface{}
+	openfeature.ProviderResolutionDetail
+}
+
+// Evaluate evaluates flagKey on p as typ, one of the AutoTypes.
+func Evaluate(ctx context.Context, p openfeature.FeatureProvider, flagKey, typ string, evalCtx openfeature.FlattenedContext) (Resolution, error) {
+	res := Resolution{Type: typ}
+	switch typ {
+	case "int":
+		r := p.IntEvaluation(ctx, flagKey, 0, evalCtx)
+		res.Value, res.ProviderResolutionDetail = r.Value, r.ProviderResolutionDetail
+	case "float":
+		r := p.FloatEvaluation(ctx, flagKey, 0, evalCtx)
+		res.Value, res.ProviderResolutionDetail = r.Value, r.ProviderResolutionDetail
+	case "bool":
+		r := p.BooleanEvaluation(ctx, flagKey, false, evalCtx)
+		res.Value, res.ProviderResolutionDetail = r.Value, r.ProviderResolutionDetail
+	case "object":
+		r := p.ObjectEvaluation(ctx, flagKey, nil, evalCtx)
+		res.Value, res.ProviderResolutionDetail = r.Value, r.ProviderResolutionDetail
+	case "string":
+		r := p.StringEvaluation(ctx, flagKey, "", evalCtx)
+		res.Value, res.ProviderResolutionDetail = r.Value, r.ProviderResolutionDetail
+	default:
+		return Resolution{}, fmt.Errorf("unknown type %q", typ)
+	}
+	return res, nil
+}
+
+// EvaluateAuto evaluates flagKey on p as the first of the AutoTypes that p serves it
+// as, for callers such as OFREP that are not told the type of the flag. Only type
+// and parse errors move on to the next type; any other error is the result.
+func EvaluateAuto(ctx context.Context, p openfeature.FeatureProvider, flagKey string, evalCtx openfeature.FlattenedContext) Resolution {
+	var res Resolution
+	for _, typ := range autoTypes {
+		res, _ = Evaluate(ctx, p, flagKey, typ, evalCtx)
+		switch res.ResolutionDetail().ErrorCode {
+		case openfeature.TypeMismatchCode, openfeature.ParseErrorCode:
+		default:
+			return res
+		}
+	}
+	return res
+}
+
+// DecodeContext decodes a JSON object into an evaluation context, with numbers
+// converted as NormalizeNumbers does.
+func DecodeContext(r io.Reader) (openfeature.FlattenedContext, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var evalCtx openfeature.FlattenedContext
+	if err := dec.Decode(&evalCtx); err != nil {
+		return nil, err
+	}
+	if evalCtx == nil {
+		return openfeature.FlattenedContext{}, nil
+	}
+	return NormalizeNumbers(evalCtx).(openfeature.FlattenedContext), nil
+}
+
+// NormalizeNumbers converts the json.Numbers of a value decoded with UseNumber into
+// int64 where they are integral and float64 otherwise, the types flags and rules
+// compare against. Maps and slices are converted in place.
+func NormalizeNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case openfeature.FlattenedContext:
+		for k, e := range t {
+			t[k] = NormalizeNumbers(e)
+		}
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = NormalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = NormalizeNumbers(e)
+		}
+	}
+	return v
+}
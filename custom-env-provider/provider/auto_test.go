@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestEvaluateAuto(t *testing.T) {
+	t.Parallel()
+
+	provider := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_ONE":    "1",
+		"FT_RATIO":  "0.5",
+		"FT_ON":     "true",
+		"FT_COLORS": `["red"]`,
+		"FT_NAME":   "checkout",
+	}))
+
+	for flagKey, test := range map[string]struct {
+		wantType  string
+		wantValue interface{}
+		wantCode  openfeature.ErrorCode
+	}{
+		"one":     {wantType: "int", wantValue: int64(1)},
+		"ratio":   {wantType: "float", wantValue: 0.5},
+		"on":      {wantType: "bool", wantValue: true},
+		"colors":  {wantType: "object", wantValue: []interface{}{"red"}},
+		"name":    {wantType: "string", wantValue: "checkout"},
+		"missing": {wantType: "int", wantValue: int64(0), wantCode: openfeature.FlagNotFoundCode},
+	} {
+		t.Run(flagKey, func(t *testing.T) {
+			t.Parallel()
+
+			res := EvaluateAuto(context.Background(), provider, flagKey, openfeature.FlattenedContext{})
+			if res.Type != test.wantType {
+				t.Errorf("type = %s, want %s", res.Type, test.wantType)
+			}
+			if diff := cmp.Diff(test.wantValue, res.Value); diff != "" {
+				t.Errorf("value mismatch (-want +got):\n%s", diff)
+			}
+			if got := res.ResolutionDetail().ErrorCode; got != test.wantCode {
+				t.Errorf("error code = %q, want %q", got, test.wantCode)
+			}
+
+			// Flag.Type reports the same type, under its longer name.
+			for _, f := range provider.Flags() {
+				if f.FlagKey == flagKey && !strings.HasPrefix(f.Type, test.wantType) {
+					t.Errorf("Flags() type = %s, want %s", f.Type, test.wantType)
+				}
+			}
+		})
+	}
+
+	if _, err := Evaluate(context.Background(), provider, "one", "integer", nil); err == nil {
+		t.Error("Evaluate() of an unknown type error = nil, want error")
+	}
+}
+
+func TestAutoTypesIsACopy(t *testing.T) {
+	t.Parallel()
+
+	AutoTypes()[0] = "string"
+	if diff := cmp.Diff([]string{"int", "float", "bool", "object", "string"}, AutoTypes()); diff != "" {
+		t.Errorf("AutoTypes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDecodeContext(t *testing.T) {
+	t.Parallel()
+
+	got, err := DecodeContext(strings.NewReader(`{"targetingKey":"user-1","age":42,"score":0.5,"tags":[1,"a"],"nested":{"n":2}}`))
+	if err != nil {
+		t.Fatalf("DecodeContext() error = %v", err)
+	}
+	want := openfeature.FlattenedContext{
+		"targetingKey": "user-1",
+		"age":          int64(42),
+		"score":        0.5,
+		"tags":         []interface{}{int64(1), "a"},
+		"nested":       map[string]interface{}{"n": int64(2)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DecodeContext() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, err := DecodeContext(strings.NewReader("null")); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("DecodeContext(null) = %v, %v; want an empty context", got, err)
+	}
+	if _, err := DecodeContext(strings.NewReader("[1]")); err == nil {
+		t.Error("DecodeContext() of an array error = nil, want error")
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// celEnv is shared by every CEL rule. Expressions are parsed but not type-checked:
+// the attributes of an evaluation context are not known in advance, so identifiers
+// such as user or version are resolved against the context when evaluated.
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv()
+})
+
+// compileCEL parses expr and plans it for evaluation. Syntax errors are reported
+// here, when the flag is loaded.
+func compileCEL(expr string) (cel.Program, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Parse(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("cel: %w", err)
+	}
+	return prg, nil
+}
+
+// evalCEL reports whether prg evaluates to true for the evaluation context. An
+// expression that refers to a missing attribute, fails, or yields anything other
+// than a boolean does not hold, like a condition on a missing attribute.
+func evalCEL(prg cel.Program, vars map[string]interface{}) bool {
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return false
+	}
+	return out == types.True
+}
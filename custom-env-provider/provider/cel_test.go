@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestCELRules(t *testing.T) {
+	const doc = `{"defaultVariant":"off","variants":{"on":true,"off":false},"rules":[` +
+		`{"cel":"user.country == \"JP\" && version >= 3","variant":"on"},` +
+		`{"cel":"targetingKey.startsWith(\"staff-\")","variant":"on"}]}`
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_NEW_CHECKOUT": doc}))
+
+	for name, test := range map[string]struct {
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.BoolResolutionDetail
+	}{
+		"first rule matches": {
+			evalCtx: openfeature.FlattenedContext{"user": map[string]interface{}{"country": "JP"}, "version": 3},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: 0},
+				},
+			},
+		},
+		"float attribute": {
+			evalCtx: openfeature.FlattenedContext{"user": map[string]interface{}{"country": "JP"}, "version": 3.5},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: 0},
+				},
+			},
+		},
+		"second rule matches": {
+			evalCtx: openfeature.FlattenedContext{"targetingKey": "staff-42"},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: 1},
+				},
+			},
+		},
+		"missing attributes": {
+			evalCtx: openfeature.FlattenedContext{"version": 4},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "off",
+				},
+			},
+		},
+		"no match": {
+			evalCtx: openfeature.FlattenedContext{"user": map[string]interface{}{"country": "US"}, "version": 4, "targetingKey": "user-1"},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "off",
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := provider.BooleanEvaluation(context.Background(), "new_checkout", false, test.evalCtx)
+			if diff := cmp.Diff(test.want, result, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCELCompileError(t *testing.T) {
+	for name, doc := range map[string]string{
+		"syntax error":   `{"defaultVariant":"off","variants":{"on":true,"off":false},"rules":[{"cel":"user.country ==","variant":"on"}]}`,
+		"if and cel":     `{"defaultVariant":"off","variants":{"on":true,"off":false},"rules":[{"if":{"attribute":"a","op":"eq","value":1},"cel":"true","variant":"on"}]}`,
+		"neither if/cel": `{"defaultVariant":"off","variants":{"on":true,"off":false},"rules":[{"variant":"on"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_FLAG": doc}))
+
+			err := provider.Init(openfeature.EvaluationContext{})
+			var initErr *openfeature.ProviderInitError
+			if !errors.As(err, &initErr) || initErr.ErrorCode != openfeature.ProviderFatalCode {
+				t.Errorf("Init() error = %v, want a ProviderInitError with %s", err, openfeature.ProviderFatalCode)
+			}
+
+			result := provider.BooleanEvaluation(context.Background(), "flag", false, nil)
+			if result.Reason != openfeature.ErrorReason {
+				t.Errorf("BooleanEvaluation() reason = %s, want %s", result.Reason, openfeature.ErrorReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// MetadataRuleIndex is the flag metadata key holding the index of the rule that
+// matched, for flags resolved with the TARGETING_MATCH reason by a rule.
+const MetadataRuleIndex = "ruleIndex"
+
+// flagDefinition is a flag document with named variants and rules choosing between
+// them, e.g. a boolean rolled out to 10% of users:
+//
+//	{"defaultVariant":"off","variants":{"on":true,"off":false},"rollout":[{"variant":"on","percentage":10},{"variant":"off","percentage":90}]}
+//
+// Prerequisites are checked first; while one is not met the default variant is
+// served. Then rules are tried in order and the first whose condition holds picks the variant;
+// otherwise the first open schedule window, then the rollout, if any, splits the
+// traffic, and the default variant is served last.
+type flagDefinition struct {
+	DefaultVariant string                 `json:"defaultVariant"`
+	Variants       map[string]interface{} `json:"variants"`
+	Prerequisites  []prerequisite         `json:"prerequisites,omitempty"`
+	Rules          []rule                 `json:"rules,omitempty"`
+	Schedule       schedule               `json:"schedule,omitempty"`
+	Rollout        rollout                `json:"rollout,omitempty"`
+
+	compileErr error
+}
+
+// compile prepares the rule conditions and schedule. A failure is kept rather than returned so
+// the document is still recognised and reports the problem on validate and evaluate.
+func (d *flagDefinition) compile() {
+	for i := range d.Rules {
+		if err := d.Rules[i].compile(); err != nil {
+			d.compileErr = fmt.Errorf("rules[%d]: %w", i, err)
+			return
+		}
+	}
+	if err := d.Schedule.compile(); err != nil {
+		d.compileErr = err
+	}
+}
+
+func (d *flagDefinition) evaluate(flagKey string, evalCtx openfeature.FlattenedContext, sc scope) (evaluation, error) {
+	if d.compileErr != nil {
+		return evaluation{}, d.compileErr
+	}
+
+	for _, pr := range d.Prerequisites {
+		met, err := pr.met(flagKey, evalCtx, sc)
+		if err != nil {
+			return evaluation{}, err
+		}
+		if !met {
+			res, err := d.pick(d.DefaultVariant, ReasonPrerequisite)
+			res.metadata = openfeature.FlagMetadata{MetadataPrerequisite: pr.Flag}
+			return res, err
+		}
+	}
+
+	for i := range d.Rules {
+		r := &d.Rules[i]
+		if r.matches(evalCtx) {
+			res, err := d.pick(r.Variant, openfeature.TargetingMatchReason)
+			res.metadata = openfeature.FlagMetadata{MetadataRuleIndex: i}
+			return res, err
+		}
+	}
+
+	if i := d.Schedule.pick(sc.now); i >= 0 {
+		res, err := d.pick(d.Schedule[i].Variant, ReasonSchedule)
+		res.metadata = openfeature.FlagMetadata{MetadataScheduleIndex: i}
+		return res, err
+	}
+
+	if len(d.Rollout) > 0 {
+		targetingKey, _ := evalCtx[openfeature.TargetingKey].(string)
+		if targetingKey == "" {
+			return evaluation{}, openfeature.NewTargetingKeyMissingResolutionError(fmt.Sprintf("flag %s is rolled out by targeting key", flagKey))
+		}
+		return d.pick(d.Rollout.pick(flagKey, targetingKey), openfeature.SplitReason)
+	}
+
+	return d.pick(d.DefaultVariant, openfeature.DefaultReason)
+}
+
+func (d *flagDefinition) pick(name string, reason openfeature.Reason) (evaluation, error) {
+	value, ok := d.Variants[name]
+	if !ok {
+		return evaluation{}, fmt.Errorf("variant %q is not defined", name)
+	}
+	return evaluation{variant: name, value: value, reason: reason}, nil
+}
+
+// validate checks that every variant the definition refers to exists.
+func (d *flagDefinition) validate() error {
+	if d.compileErr != nil {
+		return d.compileErr
+	}
+	if _, ok := d.Variants[d.DefaultVariant]; !ok {
+		return fmt.Errorf("default variant %q is not defined", d.DefaultVariant)
+	}
+	for i, pr := range d.Prerequisites {
+		if err := pr.validate(); err != nil {
+			return fmt.Errorf("prerequisites[%d]: %w", i, err)
+		}
+	}
+	for i, r := range d.Rules {
+		if _, ok := d.Variants[r.Variant]; !ok {
+			return fmt.Errorf("rules[%d]: variant %q is not defined", i, r.Variant)
+		}
+	}
+	if err := d.Schedule.validate(d.Variants); err != nil {
+		return err
+	}
+	if len(d.Rollout) > 0 {
+		if err := d.Rollout.validate(d.Variants); err != nil {
+			return fmt.Errorf("rollout: %w", err)
+		}
+	}
+	return nil
+}
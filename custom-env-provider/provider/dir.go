@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// kubernetesDataDir is the symlink Kubernetes swaps atomically when a mounted
+// ConfigMap or Secret changes.
+const kubernetesDataDir = "..data"
+
+// DirSource serves flag values from a directory in which every file name is a key
+// and the file content its value, the layout of a Kubernetes ConfigMap or Secret
+// volume. File names are upper-cased, so both FT_MY_FEATURE and ft_my_feature
+// serve the flag my_feature under the default prefix. Trailing newlines are trimmed.
+//
+// When the directory is a Kubernetes volume the files are read through the ..data
+// symlink, so a reload never mixes files from before and after an update.
+type DirSource struct {
+	dir string
+
+	mu   sync.RWMutex
+	vars map[string]string
+}
+
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{
+		dir:  dir,
+		vars: map[string]string{},
+	}
+}
+
+// WithDir reads flags from the directory at dir instead of the process environment.
+// Once the provider is initialized the directory is polled for changes, including
+// Kubernetes' atomic symlink swaps; see WithPollInterval.
+func WithDir(dir string) ProviderOption {
+	return WithSource(NewDirSource(dir))
+}
+
+func (s *DirSource) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.vars[key]
+	return val, ok
+}
+
+func (s *DirSource) Keys() ([]string, error) {
+	vars, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.vars = vars
+	s.mu.Unlock()
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// version returns the target of the ..data symlink when there is one, and a hash
+// of every file otherwise.
+func (s *DirSource) version() (string, error) {
+	if target, err := os.Readlink(filepath.Join(s.dir, kubernetesDataDir)); err == nil {
+		return target, nil
+	}
+
+	vars, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%q\n", k, vars[k])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (s *DirSource) read() (map[string]string, error) {
+	dir := s.dir
+	if resolved, err := filepath.EvalSymlinks(filepath.Join(s.dir, kubernetesDataDir)); err == nil {
+		dir = resolved
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string, len(entries))
+	for _, e := range entries {
+		// Skip Kubernetes bookkeeping entries such as ..data and ..2024_01_01_00_00_00.0
+		// as well as other hidden files.
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		vars[strings.ToUpper(e.Name())] = strings.TrimRight(string(b), "\r\n")
+	}
+	return vars, nil
+}
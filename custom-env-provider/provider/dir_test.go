@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestDirSource(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "FT_MY_FEATURE"), "true\n")
+	writeFile(t, filepath.Join(dir, "ft_count"), "42\r\n")
+	writeFile(t, filepath.Join(dir, "OTHER_FLAG"), "ignored")
+	writeFile(t, filepath.Join(dir, ".hidden"), "ignored")
+	if err := os.Mkdir(filepath.Join(dir, "FT_SUBDIR"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewSimpleEnvProvider(WithDir(dir))
+
+	if got := provider.BooleanEvaluation(context.Background(), "my_feature", false, nil).Value; !got {
+		t.Error("my_feature = false, want true")
+	}
+	if got := provider.IntEvaluation(context.Background(), "count", 0, nil).Value; got != 42 {
+		t.Errorf("count = %d, want 42", got)
+	}
+	want := []string{"FT_COUNT", "FT_MY_FEATURE"}
+	var got []string
+	for key := range provider.snapshot.Load().flags {
+		got = append(got, key)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("snapshot keys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDirSourceKubernetesSwap(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	swapConfigMap(t, dir, "..2024_01_01", map[string]string{"FT_MY_FEATURE": "false"})
+	if err := os.Symlink(filepath.Join(kubernetesDataDir, "FT_MY_FEATURE"), filepath.Join(dir, "FT_MY_FEATURE")); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewSimpleEnvProvider(WithDir(dir), WithPollInterval(10*time.Millisecond))
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	if provider.BooleanEvaluation(context.Background(), "my_feature", true, nil).Value {
+		t.Fatal("got true before swap, want false")
+	}
+
+	swapConfigMap(t, dir, "..2024_01_02", map[string]string{"FT_MY_FEATURE": "true"})
+
+	select {
+	case event := <-provider.EventChannel():
+		if diff := cmp.Diff([]string{"my_feature"}, event.FlagChanges); diff != "" {
+			t.Errorf("flag changes mismatch (-want +got):\n%s", diff)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event after swap")
+	}
+	if !provider.BooleanEvaluation(context.Background(), "my_feature", false, nil).Value {
+		t.Error("got false after swap, want true")
+	}
+}
+
+// swapConfigMap writes files into a timestamped directory and atomically points
+// ..data at it, the way the kubelet updates a mounted ConfigMap.
+func swapConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+
+	if err := os.Mkdir(filepath.Join(dir, version), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		writeFile(t, filepath.Join(dir, version, name), content)
+	}
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(version, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, kubernetesDataDir)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// evaluator is a flag document that picks one of its variants for an evaluation context.
+type evaluator interface {
+	evaluate(flagKey string, evalCtx openfeature.FlattenedContext, sc scope) (evaluation, error)
+	validate() error
+}
+
+// scope is what an evaluator may depend on besides the evaluation context.
+type scope struct {
+	now time.Time
+
+	// valueOf resolves another flag for the same context, to check prerequisites;
+	// path holds the flags whose prerequisites are being checked, outermost first.
+	valueOf func(flagKey string, evalCtx openfeature.FlattenedContext, sc scope) (interface{}, error)
+	path    []string
+}
+
+func (p *SimpleEnvProvider) scope() scope {
+	return scope{now: p.now(), valueOf: p.prerequisiteValue}
+}
+
+// evaluation is the variant an evaluator picked and why.
+type evaluation struct {
+	variant  string
+	value    interface{}
+	reason   openfeature.Reason
+	metadata openfeature.FlagMetadata
+}
+
+// parseFlagDocument reports whether val is a flag document and returns its evaluator.
+// A document is a JSON object carrying both defaultVariant and variants: a variants
+// array is the from-env format (see variantFlag), a variants object a flag
+// definition (see flagDefinition). Anything else is treated as a plain value.
+func parseFlagDocument(val string) (evaluator, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(val), "{") {
+		return nil, false
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(val), &raw); err != nil {
+		return nil, false
+	}
+	if _, ok := raw["defaultVariant"]; !ok {
+		return nil, false
+	}
+	variants, ok := raw["variants"]
+	if !ok {
+		return nil, false
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(variants)), "[") {
+		var flag variantFlag
+		if err := json.Unmarshal([]byte(val), &flag); err != nil {
+			return nil, false
+		}
+		return &flag, true
+	}
+
+	var def flagDefinition
+	if err := json.Unmarshal([]byte(val), &def); err != nil {
+		return nil, false
+	}
+	def.compile()
+	return &def, true
+}
+
+// resolutionError converts an evaluator error into a resolution error. Errors that
+// are not already resolution errors or prerequisite cycles are malformed documents.
+func resolutionError(err error) openfeature.ResolutionError {
+	var re openfeature.ResolutionError
+	if errors.As(err, &re) {
+		return re
+	}
+	var cycle *cycleError
+	if errors.As(err, &cycle) {
+		return openfeature.NewGeneralResolutionError(err.Error())
+	}
+	return openfeature.NewParseErrorResolutionError(err.Error())
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestParseFlagDocument(t *testing.T) {
+	for name, test := range map[string]struct {
+		val    string
+		wantOK bool
+	}{
+		"variant document": {
+			val:    `{"defaultVariant":"on","variants":[{"name":"on","value":true}]}`,
+			wantOK: true,
+		},
+		"flag definition": {
+			val:    `{"defaultVariant":"on","variants":{"on":true}}`,
+			wantOK: true,
+		},
+		"plain object": {
+			val:    `{"limit":10}`,
+			wantOK: false,
+		},
+		"scalar": {
+			val:    "true",
+			wantOK: false,
+		},
+		"malformed json": {
+			val:    `{"defaultVariant":`,
+			wantOK: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, ok := parseFlagDocument(test.val)
+			if ok != test.wantOK {
+				t.Errorf("parseFlagDocument(%q) ok = %v, want %v", test.val, ok, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolutionError(t *testing.T) {
+	targeting := openfeature.NewTargetingKeyMissingResolutionError("no key")
+	if got := resolutionError(targeting); got.Error() != targeting.Error() {
+		t.Errorf("resolutionError(%v) = %v, want it unchanged", targeting, got)
+	}
+
+	want := openfeature.NewParseErrorResolutionError("broken")
+	if got := resolutionError(errors.New("broken")); got.Error() != want.Error() {
+		t.Errorf("resolutionError(broken) = %v, want %v", got, want)
+	}
+}
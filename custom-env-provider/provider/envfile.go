@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// EnvFileSource serves flag values from a dotenv file such as .env or .envrc.
+// The file is re-read whenever its keys are listed, i.e. on every provider Reload.
+type EnvFileSource struct {
+	path string
+
+	mu   sync.RWMutex
+	vars map[string]string
+
+	// last observed file state, used by version to avoid re-hashing an unchanged file
+	statMu  sync.Mutex
+	modTime time.Time
+	size    int64
+	sum     string
+}
+
+func NewEnvFileSource(path string) *EnvFileSource {
+	return &EnvFileSource{
+		path: path,
+		vars: map[string]string{},
+	}
+}
+
+// WithEnvFile reads flags from the dotenv file at path instead of the process
+// environment. Once the provider is initialized the file is polled for changes and
+// the flags are reloaded without a restart; see WithPollInterval.
+func WithEnvFile(path string) ProviderOption {
+	return WithSource(NewEnvFileSource(path))
+}
+
+func (s *EnvFileSource) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.vars[key]
+	return val, ok
+}
+
+func (s *EnvFileSource) Keys() ([]string, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars, err := ParseEnvFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	s.mu.Lock()
+	s.vars = vars
+	s.mu.Unlock()
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// version returns the hash of the file content. The file is only re-hashed when its
+// modification time or size changed since the last call.
+func (s *EnvFileSource) version() (string, error) {
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", err
+	}
+	if s.sum != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.sum, nil
+	}
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	s.modTime, s.size, s.sum = info.ModTime(), info.Size(), hex.EncodeToString(sum[:])
+	return s.sum, nil
+}
+
+// ParseEnvFile parses dotenv syntax:
+//
+//	# comments and blank lines are ignored
+//	export KEY=value          # the export prefix is optional
+//	KEY='single quoted'       # taken literally, may span lines
+//	KEY="double quoted\n"     # supports \n, \t, \" and \\ escapes, may span lines
+//	KEY= # comment            # a value may be empty, the comment is not the value
+//	KEY={"multi": [           # unquoted JSON continues until it is complete
+//	  1, 2]}
+//
+// Anything but a comment after the closing quote of a quoted value is an error.
+// Unquoted JSON that is still incomplete when the next KEY= line or the end of the
+// file is reached is reported at the line it starts on.
+func ParseEnvFile(r io.Reader) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNo := 0
+	next := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		lineNo++
+		return scanner.Text(), true
+	}
+
+	for {
+		line, ok := next()
+		if !ok {
+			break
+		}
+		start := lineNo
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		trimmed = trimExport(trimmed)
+
+		key, rest, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing '='", start)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: invalid key %q", start, key)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var val string
+		switch {
+		case strings.HasPrefix(rest, "'") || strings.HasPrefix(rest, `"`):
+			quote := rest[0]
+			body := rest[1:]
+			for {
+				if end := closingQuote(body, quote); end >= 0 {
+					val = body[:end]
+					if after := strings.TrimLeft(body[end+1:], " \t"); after != "" && !strings.HasPrefix(after, "#") {
+						return nil, fmt.Errorf("line %d: unexpected %q after quoted value for %s", lineNo, after, key)
+					}
+					break
+				}
+				cont, ok := next()
+				if !ok {
+					return nil, fmt.Errorf("line %d: unterminated quoted value for %s", start, key)
+				}
+				body += "\n" + cont
+			}
+			if quote == '"' {
+				val = unescapeDoubleQuoted(val)
+			}
+		case strings.HasPrefix(rest, "{") || strings.HasPrefix(rest, "["):
+			val = strings.TrimSpace(rest)
+			for !json.Valid([]byte(val)) {
+				cont, ok := next()
+				if !ok || isAssignment(cont) {
+					return nil, fmt.Errorf("line %d: incomplete JSON value for %s", start, key)
+				}
+				val += "\n" + cont
+			}
+		default:
+			if strings.HasPrefix(rest, "#") {
+				rest = ""
+			} else if i := strings.Index(rest, " #"); i >= 0 {
+				rest = rest[:i]
+			}
+			val = strings.TrimSpace(rest)
+		}
+
+		vars[key] = val
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// trimExport removes the optional export prefix, and the blanks after it, from a line.
+func trimExport(line string) string {
+	rest, ok := strings.CutPrefix(line, "export")
+	if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return line
+	}
+	return strings.TrimLeft(rest, " \t")
+}
+
+// isAssignment reports whether line starts a new variable, i.e. looks like KEY=
+// with an optional export prefix. Such a line cannot continue a JSON value.
+func isAssignment(line string) bool {
+	key, _, ok := strings.Cut(trimExport(strings.TrimSpace(line)), "=")
+	key = strings.TrimRight(key, " \t")
+	if !ok || key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// closingQuote returns the index of the first unescaped quote in s, or -1.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeDoubleQuoted(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
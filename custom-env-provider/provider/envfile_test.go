@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	for name, test := range map[string]struct {
+		input   string
+		want    map[string]string
+		wantErr string
+	}{
+		"plain values and comments": {
+			input: "# flags\n\nFT_A=true\nFT_B = 42 # trailing comment\n",
+			want:  map[string]string{"FT_A": "true", "FT_B": "42"},
+		},
+		"export prefix": {
+			input: "export FT_A=yes\n",
+			want:  map[string]string{"FT_A": "yes"},
+		},
+		"export prefix with tab and spaces": {
+			input: "export\tFT_A=yes\nexport   FT_B=no\nexported=1\n",
+			want:  map[string]string{"FT_A": "yes", "FT_B": "no", "exported": "1"},
+		},
+		"single quoted": {
+			input: `export AM_I_YELLOW='{"defaultVariant":"not-yellow","variants":[]}'` + "\n",
+			want:  map[string]string{"AM_I_YELLOW": `{"defaultVariant":"not-yellow","variants":[]}`},
+		},
+		"double quoted with escapes": {
+			input: `FT_A="line1\nline2 \"quoted\" # not a comment"`,
+			want:  map[string]string{"FT_A": "line1\nline2 \"quoted\" # not a comment"},
+		},
+		"multi-line quoted": {
+			input: "FT_A='{\n  \"a\": 1\n}'\nFT_B=2\n",
+			want:  map[string]string{"FT_A": "{\n  \"a\": 1\n}", "FT_B": "2"},
+		},
+		"multi-line unquoted json": {
+			input: "FT_A={\n  \"limits\": [1, 2]\n}\nFT_B=2\n",
+			want:  map[string]string{"FT_A": "{\n  \"limits\": [1, 2]\n}", "FT_B": "2"},
+		},
+		"empty value": {
+			input: "FT_BANNER=\n",
+			want:  map[string]string{"FT_BANNER": ""},
+		},
+		"comment as value": {
+			input: "FT_A= # comment\nFT_B=#comment\n",
+			want:  map[string]string{"FT_A": "", "FT_B": ""},
+		},
+		"comment after quoted value": {
+			input: "FT_A='a b' # comment\nFT_B=\"c\"\t\n",
+			want:  map[string]string{"FT_A": "a b", "FT_B": "c"},
+		},
+		"text after quoted value": {
+			input:   "FT_OK=1\nFT_A='a' b\n",
+			wantErr: `line 2: unexpected "b" after quoted value for FT_A`,
+		},
+		"text after multi-line quoted value": {
+			input:   "FT_A='{\n}'x\n",
+			wantErr: `line 2: unexpected "x" after quoted value for FT_A`,
+		},
+		"missing equals": {
+			input:   "FT_A\n",
+			wantErr: "line 1: missing '='",
+		},
+		"unterminated quote": {
+			input:   "FT_A='open\n",
+			wantErr: "line 1: unterminated quoted value for FT_A",
+		},
+		"incomplete json": {
+			input:   "FT_A={\"a\":\n",
+			wantErr: "line 1: incomplete JSON value for FT_A",
+		},
+		"incomplete json before next key": {
+			input:   "FT_OK=1\nFT_A={\"a\":\n  \"b\": \"c=d\",\nexport FT_B=2\nFT_C=3\n",
+			wantErr: "line 2: incomplete JSON value for FT_A",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseEnvFile(strings.NewReader(test.input))
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParseEnvFile() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEnvFile() error = %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ParseEnvFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// replaceFile swaps in new content with a rename, as editors and config management
+// do, so that the watcher never sees the file half written.
+func replaceFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithEnvFileReload(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("export FT_MY_FEATURE=false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewSimpleEnvProvider(WithEnvFile(path), WithPollInterval(10*time.Millisecond))
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	if provider.BooleanEvaluation(context.Background(), "my_feature", true, nil).Value {
+		t.Fatal("got true before file change, want false")
+	}
+
+	replaceFile(t, path, "export FT_MY_FEATURE=true\n")
+
+	select {
+	case event := <-provider.EventChannel():
+		if diff := cmp.Diff([]string{"my_feature"}, event.FlagChanges); diff != "" {
+			t.Errorf("flag changes mismatch (-want +got):\n%s", diff)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event after file change")
+	}
+	if !provider.BooleanEvaluation(context.Background(), "my_feature", false, nil).Value {
+		t.Error("got false after file change, want true")
+	}
+
+	replaceFile(t, path, "export FT_MY_FEATURE='unterminated\n")
+	select {
+	case event := <-provider.EventChannel():
+		if event.EventType != openfeature.ProviderError {
+			t.Errorf("event type = %s, want %s", event.EventType, openfeature.ProviderError)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event after broken file")
+	}
+	if !provider.BooleanEvaluation(context.Background(), "my_feature", false, nil).Value {
+		t.Error("broken file replaced the last good flags")
+	}
+}
+
+func TestWithEnvFileRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	good := []byte("export FT_MY_FEATURE=true\n")
+	if err := os.WriteFile(path, good, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewSimpleEnvProvider(WithEnvFile(path), WithPollInterval(10*time.Millisecond))
+	if err := openfeature.SetNamedProviderAndWait("envfile-recovery", provider); err != nil {
+		t.Fatalf("SetNamedProviderAndWait() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = openfeature.SetNamedProvider("envfile-recovery", openfeature.NoopProvider{})
+	})
+	client := openfeature.NewClient("envfile-recovery")
+
+	ready := make(chan struct{}, 1)
+	errored := make(chan struct{}, 1)
+	notify := func(ch chan struct{}) openfeature.EventCallback {
+		callback := func(openfeature.EventDetails) {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+		return &callback
+	}
+	onReady, onError := notify(ready), notify(errored)
+	client.AddHandler(openfeature.ProviderReady, onReady)
+	client.AddHandler(openfeature.ProviderError, onError)
+
+	waitFor := func(events <-chan struct{}, want openfeature.State) {
+		t.Helper()
+		select {
+		case <-events:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no event for %s, client state = %s", want, client.State())
+		}
+		if got := client.State(); got != want {
+			t.Errorf("client state = %s, want %s", got, want)
+		}
+	}
+
+	replaceFile(t, path, "export FT_MY_FEATURE='unterminated\n")
+	waitFor(errored, openfeature.ErrorState)
+
+	// Adding the READY handler ran it at once for the provider that was already
+	// ready; drop that run before waiting for the recovery.
+	select {
+	case <-ready:
+	default:
+	}
+
+	// Reverting to the last good content changes no flags, but must still clear
+	// the error in the SDK.
+	replaceFile(t, path, string(good))
+	waitFor(ready, openfeature.ReadyState)
+}
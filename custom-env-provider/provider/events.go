@@ -0,0 +1,21 @@
+package provider
+
+import "github.com/open-feature/go-sdk/openfeature"
+
+// eventBufferSize bounds the events queued for the SDK. Events beyond it are
+// dropped rather than blocking a reload when nobody is listening.
+const eventBufferSize = 16
+
+var _ openfeature.EventHandler = (*SimpleEnvProvider)(nil)
+
+// EventChannel implements openfeature.EventHandler.
+func (p *SimpleEnvProvider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+func (p *SimpleEnvProvider) emit(event openfeature.Event) {
+	select {
+	case p.events <- event:
+	default:
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestReloadEvents(t *testing.T) {
+	t.Parallel()
+
+	src := MapSource{"FT_A": "1", "FT_B": "2", "FT_C": "3"}
+	provider := NewSimpleEnvProvider(WithSource(src))
+
+	// Reloads before Init do not emit.
+	src["FT_A"] = "0"
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	select {
+	case event := <-provider.EventChannel():
+		t.Fatalf("unexpected event before changes: %+v", event)
+	default:
+	}
+
+	src["FT_A"] = "10"
+	delete(src, "FT_B")
+	src["FT_D"] = "4"
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	select {
+	case event := <-provider.EventChannel():
+		if event.EventType != openfeature.ProviderConfigChange {
+			t.Errorf("event type = %s, want %s", event.EventType, openfeature.ProviderConfigChange)
+		}
+		if diff := cmp.Diff([]string{"a", "b", "d"}, event.FlagChanges); diff != "" {
+			t.Errorf("flag changes mismatch (-want +got):\n%s", diff)
+		}
+	default:
+		t.Fatal("no event after changes")
+	}
+
+	// An unchanged reload does not emit.
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	select {
+	case event := <-provider.EventChannel():
+		t.Fatalf("unexpected event without changes: %+v", event)
+	default:
+	}
+}
+
+func TestReloadEventHandler(t *testing.T) {
+	src := MapSource{"FT_MY_FEATURE": "false"}
+	provider := NewSimpleEnvProvider(WithSource(src))
+	if err := openfeature.SetNamedProviderAndWait("events", provider); err != nil {
+		t.Fatalf("SetNamedProviderAndWait() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = openfeature.SetNamedProvider("events", openfeature.NoopProvider{})
+	})
+	t.Cleanup(provider.Shutdown)
+
+	got := make(chan openfeature.EventDetails, 1)
+	callback := func(details openfeature.EventDetails) {
+		got <- details
+	}
+	client := openfeature.NewClient("events")
+	client.AddHandler(openfeature.ProviderConfigChange, &callback)
+
+	src["FT_MY_FEATURE"] = "true"
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	select {
+	case details := <-got:
+		if diff := cmp.Diff([]string{"my_feature"}, details.FlagChanges); diff != "" {
+			t.Errorf("flag changes mismatch (-want +got):\n%s", diff)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+}
@@ -0,0 +1,379 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// MetadataResolvedBy is the flag metadata key MultiProvider sets to the name of the
+// child provider whose result it returned.
+const MetadataResolvedBy = "resolvedBy"
+
+// Strategy decides which child result a MultiProvider returns.
+type Strategy int
+
+const (
+	// FirstMatch returns the result of the first child that has a value for the
+	// flag. A child error other than FLAG_NOT_FOUND is returned as is.
+	FirstMatch Strategy = iota
+	// FirstSuccess returns the result of the first child that has a value for the
+	// flag and resolves it without error. When none does, the first child error
+	// other than FLAG_NOT_FOUND is returned.
+	FirstSuccess
+	// Comparison evaluates every child concurrently and returns the result of the
+	// first successful one, or else the first child error other than FLAG_NOT_FOUND. When the successful children disagree on the value the
+	// mismatch handler is called; see WithMismatchHandler.
+	Comparison
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case FirstMatch:
+		return "first-match"
+	case FirstSuccess:
+		return "first-success"
+	case Comparison:
+		return "comparison"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// ChildResult is the result a child of a MultiProvider gave for a flag.
+type ChildResult struct {
+	Provider string
+	Value    interface{}
+	Detail   openfeature.ProviderResolutionDetail
+}
+
+// MultiProvider serves flags from an ordered list of providers, e.g. a
+// SimpleEnvProvider backed by the process environment, then one backed by a
+// dotenv file, then an in-memory provider with defaults.
+//
+// A child has no value for a flag when it reports FLAG_NOT_FOUND, or the DEFAULT
+// reason without a variant as SimpleEnvProvider does with WithFlagNotFoundError(false).
+// Children without a value are skipped by every strategy.
+type MultiProvider struct {
+	providers  []openfeature.FeatureProvider
+	names      []string
+	strategy   Strategy
+	onMismatch func(flagKey string, results []ChildResult)
+
+	state  atomic.Value // openfeature.State
+	events chan openfeature.Event
+
+	mu   sync.Mutex
+	done chan struct{} // closed by Shutdown to stop forwarding events
+	wg   sync.WaitGroup
+}
+
+type MultiProviderOption func(*MultiProvider)
+
+// NewMultiProvider combines providers, which are consulted in order, using the
+// FirstMatch strategy unless WithStrategy says otherwise.
+func NewMultiProvider(providers []openfeature.FeatureProvider, opts ...MultiProviderOption) *MultiProvider {
+	m := &MultiProvider{
+		providers: providers,
+		names:     childNames(providers),
+		strategy:  FirstMatch,
+		events:    make(chan openfeature.Event, eventBufferSize),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func WithStrategy(s Strategy) MultiProviderOption {
+	return func(m *MultiProvider) {
+		m.strategy = s
+	}
+}
+
+// WithMismatchHandler sets the function the Comparison strategy calls with every
+// child result when children disagree on a flag value. It is called synchronously,
+// so it should be quick.
+func WithMismatchHandler(fn func(flagKey string, results []ChildResult)) MultiProviderOption {
+	return func(m *MultiProvider) {
+		m.onMismatch = fn
+	}
+}
+
+// childNames returns the metadata names of providers, suffixed with their position
+// where two children share a name.
+func childNames(providers []openfeature.FeatureProvider) []string {
+	count := make(map[string]int, len(providers))
+	for _, p := range providers {
+		count[p.Metadata().Name]++
+	}
+
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		name := p.Metadata().Name
+		if count[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, i)
+		}
+		names[i] = name
+	}
+	return names
+}
+
+func (m *MultiProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "multi-provider",
+	}
+}
+
+func (m *MultiProvider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+var (
+	_ openfeature.StateHandler = (*MultiProvider)(nil)
+	_ openfeature.EventHandler = (*MultiProvider)(nil)
+)
+
+// Init initializes every child that needs it and starts forwarding child events.
+// Children that fail to initialize are reported together, but the others are
+// still initialized.
+func (m *MultiProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	var errs []error
+	for i, p := range m.providers {
+		h, ok := p.(openfeature.StateHandler)
+		if !ok {
+			continue
+		}
+		if err := h.Init(evalCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.names[i], err))
+		}
+	}
+
+	m.mu.Lock()
+	if m.done == nil {
+		m.done = make(chan struct{})
+		m.forwardEvents(m.done)
+	}
+	m.mu.Unlock()
+
+	if err := errors.Join(errs...); err != nil {
+		m.state.Store(openfeature.ErrorState)
+		return err
+	}
+	m.state.Store(openfeature.ReadyState)
+	return nil
+}
+
+// Shutdown stops forwarding events and shuts every child down.
+func (m *MultiProvider) Shutdown() {
+	m.mu.Lock()
+	if m.done != nil {
+		close(m.done)
+		m.done = nil
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+
+	for _, p := range m.providers {
+		if h, ok := p.(openfeature.StateHandler); ok {
+			h.Shutdown()
+		}
+	}
+	m.state.Store(openfeature.NotReadyState)
+}
+
+// Status reflects the last Init: ERROR if any child failed it, READY otherwise,
+// and NOT_READY before the first Init and after Shutdown. It does not follow the
+// children afterwards; their events are forwarded on EventChannel instead.
+func (m *MultiProvider) Status() openfeature.State {
+	if s, ok := m.state.Load().(openfeature.State); ok {
+		return s
+	}
+	return openfeature.NotReadyState
+}
+
+// EventChannel implements openfeature.EventHandler. It carries the events of every
+// child that emits any.
+func (m *MultiProvider) EventChannel() <-chan openfeature.Event {
+	return m.events
+}
+
+func (m *MultiProvider) forwardEvents(done <-chan struct{}) {
+	for _, p := range m.providers {
+		h, ok := p.(openfeature.EventHandler)
+		if !ok {
+			continue
+		}
+
+		m.wg.Add(1)
+		go func(events <-chan openfeature.Event) {
+			defer m.wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					select {
+					case m.events <- event:
+					default:
+					}
+				}
+			}
+		}(h.EventChannel())
+	}
+}
+
+func (m *MultiProvider) BooleanEvaluation(ctx context.Context, flagKey string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	val, detail := resolveChildren(m, flagKey, defaultValue, func(p openfeature.FeatureProvider) (bool, openfeature.ProviderResolutionDetail) {
+		res := p.BooleanEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.BoolResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (m *MultiProvider) StringEvaluation(ctx context.Context, flagKey string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	val, detail := resolveChildren(m, flagKey, defaultValue, func(p openfeature.FeatureProvider) (string, openfeature.ProviderResolutionDetail) {
+		res := p.StringEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.StringResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (m *MultiProvider) IntEvaluation(ctx context.Context, flagKey string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	val, detail := resolveChildren(m, flagKey, defaultValue, func(p openfeature.FeatureProvider) (int64, openfeature.ProviderResolutionDetail) {
+		res := p.IntEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.IntResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (m *MultiProvider) FloatEvaluation(ctx context.Context, flagKey string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	val, detail := resolveChildren(m, flagKey, defaultValue, func(p openfeature.FeatureProvider) (float64, openfeature.ProviderResolutionDetail) {
+		res := p.FloatEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.FloatResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (m *MultiProvider) ObjectEvaluation(ctx context.Context, flagKey string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	val, detail := resolveChildren(m, flagKey, defaultValue, func(p openfeature.FeatureProvider) (interface{}, openfeature.ProviderResolutionDetail) {
+		res := p.ObjectEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.InterfaceResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+// resolveChildren applies the strategy of m to the results eval gives for each child.
+func resolveChildren[T any](m *MultiProvider, flagKey string, defaultValue T, eval func(openfeature.FeatureProvider) (T, openfeature.ProviderResolutionDetail)) (T, openfeature.ProviderResolutionDetail) {
+	if len(m.providers) == 0 {
+		return defaultValue, openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %s is not set: no providers", flagKey)),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+
+	if m.strategy == Comparison {
+		return compareChildren(m, flagKey, eval)
+	}
+
+	var (
+		val    T
+		detail openfeature.ProviderResolutionDetail
+
+		errVal    T
+		errDetail *openfeature.ProviderResolutionDetail
+	)
+	for i, p := range m.providers {
+		val, detail = eval(p)
+		if !hasValue(detail) {
+			continue
+		}
+		if detail.Error() != nil && m.strategy == FirstSuccess {
+			if errDetail == nil {
+				d := m.resolvedBy(i, detail)
+				errVal, errDetail = val, &d
+			}
+			continue
+		}
+		return val, m.resolvedBy(i, detail)
+	}
+	if errDetail != nil {
+		return errVal, *errDetail
+	}
+	// No child had a value: report what the last one said.
+	return val, detail
+}
+
+func compareChildren[T any](m *MultiProvider, flagKey string, eval func(openfeature.FeatureProvider) (T, openfeature.ProviderResolutionDetail)) (T, openfeature.ProviderResolutionDetail) {
+	vals := make([]T, len(m.providers))
+	details := make([]openfeature.ProviderResolutionDetail, len(m.providers))
+
+	var wg sync.WaitGroup
+	for i, p := range m.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals[i], details[i] = eval(p)
+		}()
+	}
+	wg.Wait()
+
+	first := -1
+	agree := true
+	for i := range m.providers {
+		if !hasValue(details[i]) || details[i].Error() != nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		} else if !reflect.DeepEqual(vals[first], vals[i]) {
+			agree = false
+		}
+	}
+
+	if !agree && m.onMismatch != nil {
+		results := make([]ChildResult, len(m.providers))
+		for i := range m.providers {
+			results[i] = ChildResult{Provider: m.names[i], Value: vals[i], Detail: details[i]}
+		}
+		m.onMismatch(flagKey, results)
+	}
+
+	if first >= 0 {
+		return vals[first], m.resolvedBy(first, details[first])
+	}
+	// No child succeeded: report the first child that had a value, as FirstSuccess does.
+	for i := range m.providers {
+		if hasValue(details[i]) {
+			return vals[i], m.resolvedBy(i, details[i])
+		}
+	}
+	return vals[0], details[0]
+}
+
+// hasValue reports whether a child had a value for the flag, successfully or not.
+func hasValue(detail openfeature.ProviderResolutionDetail) bool {
+	if detail.ResolutionDetail().ErrorCode == openfeature.FlagNotFoundCode {
+		return false
+	}
+	return detail.Reason != openfeature.DefaultReason || detail.Variant != ""
+}
+
+// resolvedBy returns detail with its flag metadata naming child i.
+func (m *MultiProvider) resolvedBy(i int, detail openfeature.ProviderResolutionDetail) openfeature.ProviderResolutionDetail {
+	metadata := make(openfeature.FlagMetadata, len(detail.FlagMetadata)+1)
+	for k, v := range detail.FlagMetadata {
+		metadata[k] = v
+	}
+	metadata[MetadataResolvedBy] = m.names[i]
+	detail.FlagMetadata = metadata
+	return detail
+}
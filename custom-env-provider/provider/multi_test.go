@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+)
+
+func newMultiChildren() []openfeature.FeatureProvider {
+	env := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_LIMIT": "10",
+		"FT_COLOR": "not a number",
+		"FT_SHADE": "dark",
+	}))
+	file := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_LIMIT":  "20",
+		"FT_COLOR":  "30",
+		"FT_THEME":  "40",
+		"FT_BROKEN": "x",
+	}))
+	defaults := memprovider.NewInMemoryProvider(map[string]memprovider.InMemoryFlag{
+		"limit": {
+			Key:            "limit",
+			State:          memprovider.Enabled,
+			DefaultVariant: "default",
+			Variants:       map[string]interface{}{"default": 50},
+		},
+		"theme": {
+			Key:            "theme",
+			State:          memprovider.Enabled,
+			DefaultVariant: "default",
+			Variants:       map[string]interface{}{"default": 60},
+		},
+		"shade": {
+			Key:            "shade",
+			State:          memprovider.Enabled,
+			DefaultVariant: "default",
+			Variants:       map[string]interface{}{"default": "dark"},
+		},
+		"size": {
+			Key:            "size",
+			State:          memprovider.Enabled,
+			DefaultVariant: "default",
+			Variants:       map[string]interface{}{"default": 70},
+		},
+	})
+	return []openfeature.FeatureProvider{env, file, defaults}
+}
+
+func TestMultiProvider(t *testing.T) {
+	for name, test := range map[string]struct {
+		strategy Strategy
+		flagKey  string
+		want     openfeature.IntResolutionDetail
+	}{
+		"first match from first child": {
+			strategy: FirstMatch,
+			flagKey:  "limit",
+			want: openfeature.IntResolutionDetail{
+				Value: 10,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       ReasonEnv,
+					FlagMetadata: openfeature.FlagMetadata{MetadataResolvedBy: "simple-env-flag-evaluator#0"},
+				},
+			},
+		},
+		"first match skips not found": {
+			strategy: FirstMatch,
+			flagKey:  "size",
+			want: openfeature.IntResolutionDetail{
+				Value: 70,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.StaticReason,
+					Variant:      "default",
+					FlagMetadata: openfeature.FlagMetadata{MetadataResolvedBy: "InMemoryProvider"},
+				},
+			},
+		},
+		"first match stops at error": {
+			strategy: FirstMatch,
+			flagKey:  "color",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError(`strconv.ParseInt: parsing "not a number": invalid syntax`),
+					Reason:          openfeature.ErrorReason,
+					FlagMetadata:    openfeature.FlagMetadata{MetadataResolvedBy: "simple-env-flag-evaluator#0"},
+				},
+			},
+		},
+		"first success skips error": {
+			strategy: FirstSuccess,
+			flagKey:  "color",
+			want: openfeature.IntResolutionDetail{
+				Value: 30,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       ReasonEnv,
+					FlagMetadata: openfeature.FlagMetadata{MetadataResolvedBy: "simple-env-flag-evaluator#1"},
+				},
+			},
+		},
+		"first success returns first error": {
+			strategy: FirstSuccess,
+			flagKey:  "shade",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError(`strconv.ParseInt: parsing "dark": invalid syntax`),
+					Reason:          openfeature.ErrorReason,
+					FlagMetadata:    openfeature.FlagMetadata{MetadataResolvedBy: "simple-env-flag-evaluator#0"},
+				},
+			},
+		},
+		"comparison returns first error": {
+			strategy: Comparison,
+			flagKey:  "broken",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError(`strconv.ParseInt: parsing "x": invalid syntax`),
+					Reason:          openfeature.ErrorReason,
+					FlagMetadata:    openfeature.FlagMetadata{MetadataResolvedBy: "simple-env-flag-evaluator#1"},
+				},
+			},
+		},
+		"first success after not found returns error": {
+			strategy: FirstSuccess,
+			flagKey:  "broken",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError(`strconv.ParseInt: parsing "x": invalid syntax`),
+					Reason:          openfeature.ErrorReason,
+					FlagMetadata:    openfeature.FlagMetadata{MetadataResolvedBy: "simple-env-flag-evaluator#1"},
+				},
+			},
+		},
+		"not found anywhere": {
+			strategy: FirstSuccess,
+			flagKey:  "missing",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag for key missing not found"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMultiProvider(newMultiChildren(), WithStrategy(test.strategy))
+			result := m.IntEvaluation(context.Background(), test.flagKey, -1, nil)
+
+			opts := cmp.Comparer(func(a, b openfeature.ResolutionError) bool { return a.Error() == b.Error() })
+			if diff := cmp.Diff(test.want, result, opts); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMultiProviderDefaultReasonIsNoValue(t *testing.T) {
+	quiet := NewSimpleEnvProvider(WithSource(MapSource{}), WithFlagNotFoundError(false))
+	backup := NewSimpleEnvProvider(WithSource(MapSource{"FT_ENABLED": "true"}))
+	m := NewMultiProvider([]openfeature.FeatureProvider{quiet, backup})
+
+	result := m.BooleanEvaluation(context.Background(), "enabled", false, nil)
+	if !result.Value || result.FlagMetadata[MetadataResolvedBy] != "simple-env-flag-evaluator#1" {
+		t.Errorf("BooleanEvaluation() = %+v, want true from the second child", result)
+	}
+}
+
+func TestMultiProviderComparison(t *testing.T) {
+	var mismatches [][]ChildResult
+	m := NewMultiProvider(newMultiChildren(),
+		WithStrategy(Comparison),
+		WithMismatchHandler(func(flagKey string, results []ChildResult) {
+			if flagKey != "limit" {
+				t.Errorf("mismatch for %s, want limit", flagKey)
+			}
+			mismatches = append(mismatches, results)
+		}),
+	)
+
+	result := m.IntEvaluation(context.Background(), "limit", -1, nil)
+	if result.Value != 10 || result.FlagMetadata[MetadataResolvedBy] != "simple-env-flag-evaluator#0" {
+		t.Errorf("IntEvaluation() = %+v, want 10 from the first child", result)
+	}
+
+	want := [][]ChildResult{{
+		{Provider: "simple-env-flag-evaluator#0", Value: int64(10), Detail: openfeature.ProviderResolutionDetail{Reason: ReasonEnv}},
+		{Provider: "simple-env-flag-evaluator#1", Value: int64(20), Detail: openfeature.ProviderResolutionDetail{Reason: ReasonEnv}},
+		{Provider: "InMemoryProvider", Value: int64(50), Detail: openfeature.ProviderResolutionDetail{Reason: openfeature.StaticReason, Variant: "default"}},
+	}}
+	if diff := cmp.Diff(want, mismatches, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+		t.Errorf("mismatches (-want +got):\n%s", diff)
+	}
+
+	mismatches = nil
+	if result := m.IntEvaluation(context.Background(), "size", -1, nil); result.Value != 70 {
+		t.Errorf("IntEvaluation(size) = %+v, want 70", result)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("got %d mismatches for a flag only one child has, want none", len(mismatches))
+	}
+}
+
+func TestMultiProviderInit(t *testing.T) {
+	ok := NewSimpleEnvProvider(WithSource(MapSource{}))
+	broken := NewSimpleEnvProvider(WithSource(failingSource{}))
+	m := NewMultiProvider([]openfeature.FeatureProvider{ok, broken})
+
+	err := m.Init(openfeature.EvaluationContext{})
+	if err == nil || !strings.Contains(err.Error(), "simple-env-flag-evaluator#1: list flags: source unavailable") {
+		t.Errorf("Init() error = %v, want the failing child's error", err)
+	}
+	if m.Status() != openfeature.ErrorState {
+		t.Errorf("Status() = %s, want %s", m.Status(), openfeature.ErrorState)
+	}
+	if ok.Status() != openfeature.ReadyState {
+		t.Errorf("healthy child Status() = %s, want %s", ok.Status(), openfeature.ReadyState)
+	}
+
+	m.Shutdown()
+	if m.Status() != openfeature.NotReadyState || ok.Status() != openfeature.NotReadyState {
+		t.Errorf("after Shutdown Status() = %s and child %s, want %s", m.Status(), ok.Status(), openfeature.NotReadyState)
+	}
+}
+
+func TestMultiProviderEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("FT_LIMIT=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	child := NewSimpleEnvProvider(WithEnvFile(path), WithPollInterval(10*time.Millisecond))
+	m := NewMultiProvider([]openfeature.FeatureProvider{child})
+	if err := m.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Shutdown)
+
+	if err := os.WriteFile(path, []byte("FT_LIMIT=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-m.EventChannel():
+		if event.EventType != openfeature.ProviderConfigChange {
+			t.Errorf("event type = %s, want %s", event.EventType, openfeature.ProviderConfigChange)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event forwarded from child")
+	}
+}
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ReasonOverride is the reason of evaluations served from a runtime override.
+const ReasonOverride = "runtime_override"
+
+// Override is a value set on a running provider with SetOverride, served in place
+// of the value held by the source until it expires or is cleared.
+type Override struct {
+	FlagKey   string
+	Value     string
+	ExpiresAt time.Time // zero when the override does not expire
+}
+
+// Flag describes a flag as the provider currently serves it.
+type Flag struct {
+	FlagKey string
+	// Raw is the value in effect: the override if there is one, else the source's.
+	Raw string
+	// Type is what Raw parses as: integer, float, boolean, object, document or string.
+	Type string
+	// Value is Raw parsed as Type.
+	Value interface{}
+	// Err is set when Raw can never be served.
+	Err error
+	// File is the _FILE variable naming the file the value was read from. Such
+	// values are secrets, so Raw and Value are left empty.
+	File     string
+	Override *Override
+	// Source describes the value the source holds for an overridden flag. It is nil
+	// when the flag is not overridden or the source does not set it.
+	Source *Flag
+}
+
+type runtimeOverride struct {
+	Override
+	fv    *flagValue
+	timer *time.Timer // drops the override once it expires; nil without a TTL
+}
+
+// stop cancels the expiry of an override that is cleared or replaced.
+func (o *runtimeOverride) stop() {
+	if o.timer != nil {
+		o.timer.Stop()
+	}
+}
+
+func (o *runtimeOverride) expired(now time.Time) bool {
+	return !o.ExpiresAt.IsZero() && !now.Before(o.ExpiresAt)
+}
+
+// SetOverride serves value for flagKey in place of the source's value, as if the
+// source held it, for ttl or until cleared when ttl is zero or negative. A flag
+// need not be set in the source to be overridden, and an override wins over the
+// evaluation context whatever the precedence. The value is validated like the
+// source's values on Init and rejected if it can never be served. Setting, clearing
+// and the expiry of an override all emit PROVIDER_CONFIGURATION_CHANGED.
+//
+// Overrides live in memory only: they do not survive a restart and are not seen by
+// other instances.
+func (p *SimpleEnvProvider) SetOverride(flagKey, value string, ttl time.Duration) error {
+	fv := parseFlagValue(value)
+	if err := fv.validate(); err != nil {
+		return fmt.Errorf("%s: %w", flagKey, err)
+	}
+	fv.override = true
+
+	o := &runtimeOverride{
+		Override: Override{FlagKey: strings.ToLower(flagKey), Value: value},
+		fv:       fv,
+	}
+	if ttl > 0 {
+		o.ExpiresAt = p.now().Add(ttl)
+	}
+
+	key := p.prefix + strings.ToUpper(flagKey)
+	p.updateOverrides(func(overrides map[string]*runtimeOverride) {
+		if old, ok := overrides[key]; ok {
+			old.stop()
+		}
+		if ttl > 0 {
+			// The timer only prompts updateOverrides to drop and announce the
+			// override; whether it has expired is decided by the provider's clock.
+			o.timer = time.AfterFunc(ttl, func() {
+				p.updateOverrides(func(map[string]*runtimeOverride) {})
+			})
+		}
+		overrides[key] = o
+	})
+	p.emitOverrideChange("flag overridden", o.FlagKey)
+	return nil
+}
+
+// ClearOverride removes the override of flagKey, reporting whether there was one.
+func (p *SimpleEnvProvider) ClearOverride(flagKey string) bool {
+	key := p.prefix + strings.ToUpper(flagKey)
+	var cleared bool
+	p.updateOverrides(func(overrides map[string]*runtimeOverride) {
+		var o *runtimeOverride
+		if o, cleared = overrides[key]; cleared {
+			o.stop()
+			delete(overrides, key)
+		}
+	})
+	if cleared {
+		p.emitOverrideChange("override cleared", strings.ToLower(flagKey))
+	}
+	return cleared
+}
+
+// ClearOverrides removes every override.
+func (p *SimpleEnvProvider) ClearOverrides() {
+	var cleared []string
+	p.updateOverrides(func(overrides map[string]*runtimeOverride) {
+		for key, o := range overrides {
+			o.stop()
+			cleared = append(cleared, o.FlagKey)
+			delete(overrides, key)
+		}
+	})
+	if len(cleared) > 0 {
+		sort.Strings(cleared)
+		p.emitOverrideChange("overrides cleared", cleared...)
+	}
+}
+
+// Overrides returns the overrides in effect, sorted by flag key.
+func (p *SimpleEnvProvider) Overrides() []Override {
+	now := p.now()
+	var overrides []Override
+	for _, o := range p.loadOverrides() {
+		if !o.expired(now) {
+			overrides = append(overrides, o.Override)
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].FlagKey < overrides[j].FlagKey
+	})
+	return overrides
+}
+
+// Flags describes every flag the provider serves, sorted by flag key: those in the
+// current snapshot and those that are overridden.
+func (p *SimpleEnvProvider) Flags() []Flag {
+	var flags []Flag
+	for _, key := range p.FlagKeys() {
+		fv, ok := p.lookup(key)
+		if !ok {
+			continue
+		}
+		f := describeFlag(key, fv)
+		if fv.override {
+			prefixed := p.prefix + strings.ToUpper(key)
+			if o, ok := p.lookupOverride(prefixed); ok {
+				f.Override = &o.Override
+			}
+			if src, ok := p.lookupSource(prefixed); ok {
+				s := describeFlag(key, src)
+				f.Source = &s
+			}
+		}
+		flags = append(flags, f)
+	}
+	return flags
+}
+
+func describeFlag(key string, fv *flagValue) Flag {
+	f := Flag{FlagKey: key, Raw: fv.raw, Err: fv.validate()}
+	f.Type, f.Value = fv.kind()
+	if fv.fileKey != "" {
+		f.File, f.Raw, f.Value = fv.fileKey, "", nil
+	}
+	return f
+}
+
+// kind reports what the value parses as, trying numbers before booleans as "1"
+// and "0" parse as both. Like ObjectEvaluation, only JSON objects and arrays are
+// objects; JSON null and quoted strings are strings.
+func (fv *flagValue) kind() (string, interface{}) {
+	switch {
+	case fv.fileErr != nil:
+		return "", nil
+	case fv.doc != nil:
+		var doc interface{}
+		_ = json.Unmarshal([]byte(fv.raw), &doc)
+		return "document", doc
+	case fv.intErr == nil:
+		return "integer", fv.intVal
+	case fv.floatErr == nil:
+		return "float", fv.floatVal
+	case fv.boolErr == nil:
+		return "boolean", fv.boolVal
+	case fv.objErr == nil && isObject(fv.objVal):
+		return "object", fv.objVal
+	default:
+		return "string", fv.raw
+	}
+}
+
+// reason is the reason of evaluations served from the value.
+func (fv *flagValue) reason() openfeature.Reason {
+	if fv.override {
+		return ReasonOverride
+	}
+	return ReasonEnv
+}
+
+// lookupOverride returns the unexpired override of a prefixed key.
+func (p *SimpleEnvProvider) lookupOverride(key string) (*runtimeOverride, bool) {
+	o, ok := p.loadOverrides()[key]
+	if !ok || o.expired(p.now()) {
+		return nil, false
+	}
+	return o, true
+}
+
+func (p *SimpleEnvProvider) loadOverrides() map[string]*runtimeOverride {
+	if m := p.overrides.Load(); m != nil {
+		return *m
+	}
+	return nil
+}
+
+// updateOverrides applies fn to a copy of the overrides, without the expired ones,
+// and publishes the copy. Like the snapshot, the published map is never modified,
+// so evaluations read it without locking. Each expired override is dropped, and its
+// expiry announced, exactly once.
+func (p *SimpleEnvProvider) updateOverrides(fn func(map[string]*runtimeOverride)) {
+	p.overrideMu.Lock()
+	now := p.now()
+	next := make(map[string]*runtimeOverride)
+	var expired []string
+	for key, o := range p.loadOverrides() {
+		if o.expired(now) {
+			expired = append(expired, o.FlagKey)
+			continue
+		}
+		next[key] = o
+	}
+	fn(next)
+	p.overrides.Store(&next)
+	p.overrideMu.Unlock()
+
+	if len(expired) > 0 {
+		sort.Strings(expired)
+		p.emitOverrideChange("override expired", expired...)
+	}
+}
+
+func (p *SimpleEnvProvider) emitOverrideChange(message string, flagKeys ...string) {
+	if p.Status() == openfeature.NotReadyState {
+		return
+	}
+	p.emit(openfeature.Event{
+		ProviderName: p.Metadata().Name,
+		EventType:    openfeature.ProviderConfigChange,
+		ProviderEventDetails: openfeature.ProviderEventDetails{
+			Message:     message,
+			FlagChanges: flagKeys,
+		},
+	})
+}
@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestSetOverride(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := NewSimpleEnvProvider(
+		WithSource(MapSource{"FT_KILL_SWITCH": "false", "FT_LIMIT": "10"}),
+		WithClock(func() time.Time { return now }),
+	)
+	ctx := context.Background()
+
+	if err := provider.SetOverride("kill_switch", "true", time.Minute); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if err := provider.SetOverride("new_flag", "blue", 0); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+
+	got := provider.BooleanEvaluation(ctx, "kill_switch", false, openfeature.FlattenedContext{})
+	if !got.Value || got.Reason != ReasonOverride {
+		t.Errorf("BooleanEvaluation() = %v, %s; want true, %s", got.Value, got.Reason, ReasonOverride)
+	}
+	if got := provider.StringEvaluation(ctx, "new_flag", "", openfeature.FlattenedContext{}); got.Value != "blue" {
+		t.Errorf("StringEvaluation() of an override without source value = %q, want blue", got.Value)
+	}
+	if got := provider.IntEvaluation(ctx, "limit", 0, openfeature.FlattenedContext{}); got.Value != 10 || got.Reason != ReasonEnv {
+		t.Errorf("IntEvaluation() of a flag without override = %d, %s; want 10, %s", got.Value, got.Reason, ReasonEnv)
+	}
+
+	want := []Override{
+		{FlagKey: "kill_switch", Value: "true", ExpiresAt: now.Add(time.Minute)},
+		{FlagKey: "new_flag", Value: "blue"},
+	}
+	if diff := cmp.Diff(want, provider.Overrides()); diff != "" {
+		t.Errorf("Overrides() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"kill_switch", "limit", "new_flag"}, provider.FlagKeys()); diff != "" {
+		t.Errorf("FlagKeys() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The override expires.
+	now = now.Add(time.Minute)
+	got = provider.BooleanEvaluation(ctx, "kill_switch", true, openfeature.FlattenedContext{})
+	if got.Value || got.Reason != ReasonEnv {
+		t.Errorf("BooleanEvaluation() after expiry = %v, %s; want false, %s", got.Value, got.Reason, ReasonEnv)
+	}
+
+	if !provider.ClearOverride("new_flag") {
+		t.Error("ClearOverride() = false, want true")
+	}
+	if provider.ClearOverride("new_flag") {
+		t.Error("ClearOverride() of a cleared override = true, want false")
+	}
+	if got := provider.StringEvaluation(ctx, "new_flag", "", openfeature.FlattenedContext{}); got.ResolutionDetail().ErrorCode != openfeature.FlagNotFoundCode {
+		t.Errorf("StringEvaluation() after clear error code = %s, want %s", got.ResolutionDetail().ErrorCode, openfeature.FlagNotFoundCode)
+	}
+}
+
+func TestSetOverrideInvalid(t *testing.T) {
+	t.Parallel()
+
+	provider := NewSimpleEnvProvider(WithSource(MapSource{}))
+	if err := provider.SetOverride("colors", `["red",`, time.Minute); err == nil {
+		t.Error("SetOverride() of invalid JSON error = nil, want error")
+	}
+	if err := provider.SetOverride("color", `{"variants":{"red":"#f00"},"defaultVariant":"blue"}`, time.Minute); err == nil {
+		t.Error("SetOverride() of an unresolvable document error = nil, want error")
+	}
+	if got := provider.Overrides(); len(got) != 0 {
+		t.Errorf("Overrides() = %v, want none", got)
+	}
+}
+
+func TestClearOverrides(t *testing.T) {
+	t.Parallel()
+
+	provider := NewSimpleEnvProvider(WithSource(MapSource{}))
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	for _, key := range []string{"b", "a"} {
+		if err := provider.SetOverride(key, "1", time.Minute); err != nil {
+			t.Fatalf("SetOverride() error = %v", err)
+		}
+		<-provider.EventChannel()
+	}
+
+	provider.ClearOverrides()
+	if got := provider.Overrides(); len(got) != 0 {
+		t.Errorf("Overrides() = %v, want none", got)
+	}
+	select {
+	case event := <-provider.EventChannel():
+		if diff := cmp.Diff([]string{"a", "b"}, event.FlagChanges); diff != "" {
+			t.Errorf("flag changes mismatch (-want +got):\n%s", diff)
+		}
+	default:
+		t.Fatal("no event after clearing overrides")
+	}
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	token := filepath.Join(t.TempDir(), "token")
+	writeFile(t, token, "s3cret")
+	provider := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_ENABLED":    "true",
+		"FT_LIMIT":      "10",
+		"FT_RATIO":      "0.5",
+		"FT_COLORS":     `["red"]`,
+		"FT_NAME":       "checkout",
+		"FT_COLOR":      `{"variants":{"red":"#f00"},"defaultVariant":"red"}`,
+		"FT_NOTHING":    "null",
+		"FT_QUOTED":     `"hi"`,
+		"FT_TOKEN_FILE": token,
+	}))
+	if err := provider.SetOverride("enabled", "false", 0); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if err := provider.SetOverride("extra", "1", 0); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+
+	want := []Flag{
+		{FlagKey: "color", Raw: `{"variants":{"red":"#f00"},"defaultVariant":"red"}`, Type: "document", Value: map[string]interface{}{"variants": map[string]interface{}{"red": "#f00"}, "defaultVariant": "red"}},
+		{FlagKey: "colors", Raw: `["red"]`, Type: "object", Value: []interface{}{"red"}},
+		{
+			FlagKey: "enabled", Raw: "false", Type: "boolean", Value: false,
+			Override: &Override{FlagKey: "enabled", Value: "false"},
+			Source:   &Flag{FlagKey: "enabled", Raw: "true", Type: "boolean", Value: true},
+		},
+		{FlagKey: "extra", Raw: "1", Type: "integer", Value: int64(1), Override: &Override{FlagKey: "extra", Value: "1"}},
+		{FlagKey: "limit", Raw: "10", Type: "integer", Value: int64(10)},
+		{FlagKey: "name", Raw: "checkout", Type: "string", Value: "checkout"},
+		{FlagKey: "nothing", Raw: "null", Type: "string", Value: "null"},
+		{FlagKey: "quoted", Raw: `"hi"`, Type: "string", Value: `"hi"`},
+		{FlagKey: "ratio", Raw: "0.5", Type: "float", Value: 0.5},
+		{FlagKey: "token", Type: "string", File: "FT_TOKEN_FILE"},
+	}
+	if diff := cmp.Diff(want, provider.Flags()); diff != "" {
+		t.Errorf("Flags() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOverrideWinsOverContext(t *testing.T) {
+	t.Parallel()
+
+	for _, pr := range []Precedence{ContextFirst, EnvFirst, EnvOnly, ContextOnly} {
+		t.Run(pr.String(), func(t *testing.T) {
+			t.Parallel()
+
+			provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_KILL_SWITCH": "false"}), WithPrecedence(pr))
+			if err := provider.SetOverride("kill_switch", "true", 0); err != nil {
+				t.Fatalf("SetOverride() error = %v", err)
+			}
+
+			got := provider.BooleanEvaluation(context.Background(), "kill_switch", false, openfeature.FlattenedContext{"kill_switch": false})
+			if !got.Value || got.Reason != ReasonOverride {
+				t.Errorf("BooleanEvaluation() = %v, %s; want true, %s", got.Value, got.Reason, ReasonOverride)
+			}
+		})
+	}
+}
+
+func TestOverrideExpiryEvent(t *testing.T) {
+	t.Parallel()
+
+	provider := NewSimpleEnvProvider(WithSource(MapSource{}))
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	if err := provider.SetOverride("kill_switch", "true", 10*time.Millisecond); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	<-provider.EventChannel()
+
+	select {
+	case event := <-provider.EventChannel():
+		if event.EventType != openfeature.ProviderConfigChange {
+			t.Errorf("event type = %s, want %s", event.EventType, openfeature.ProviderConfigChange)
+		}
+		if diff := cmp.Diff([]string{"kill_switch"}, event.FlagChanges); diff != "" {
+			t.Errorf("flag changes mismatch (-want +got):\n%s", diff)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event after the override expired")
+	}
+	if got := provider.Overrides(); len(got) != 0 {
+		t.Errorf("Overrides() = %v, want none", got)
+	}
+
+	// A cleared override does not announce its expiry.
+	if err := provider.SetOverride("kill_switch", "true", 10*time.Millisecond); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	<-provider.EventChannel()
+	provider.ClearOverride("kill_switch")
+	<-provider.EventChannel()
+	select {
+	case event := <-provider.EventChannel():
+		t.Errorf("unexpected event after clearing the override: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
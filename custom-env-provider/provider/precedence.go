@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Precedence decides which of the evaluation context and the source serves a flag.
+type Precedence int
+
+const (
+	// ContextFirst serves a flag from the evaluation context when present and falls
+	// back to the source. This is the default.
+	ContextFirst Precedence = iota
+	// EnvFirst serves a flag from the source when set and falls back to the evaluation context.
+	EnvFirst
+	// EnvOnly ignores the evaluation context.
+	EnvOnly
+	// ContextOnly ignores the source.
+	ContextOnly
+)
+
+func (pr Precedence) String() string {
+	switch pr {
+	case ContextFirst:
+		return "context-first"
+	case EnvFirst:
+		return "env-first"
+	case EnvOnly:
+		return "env-only"
+	case ContextOnly:
+		return "context-only"
+	default:
+		return "unknown"
+	}
+}
+
+// WithPrecedence sets the order in which the evaluation context and the source are consulted.
+func WithPrecedence(pr Precedence) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.precedence = pr
+	}
+}
+
+// WithContextOverrides restricts the flags that may be served from the evaluation
+// context to flagKeys. Attributes named after any other flag are ignored, so callers
+// cannot flip ops-controlled values such as kill switches. Without this option every
+// flag may be overridden.
+func WithContextOverrides(flagKeys ...string) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.overridable = make(map[string]struct{}, len(flagKeys))
+		for _, k := range flagKeys {
+			p.overridable[strings.ToUpper(k)] = struct{}{}
+		}
+	}
+}
+
+// resolve applies the configured precedence. When fromCtx is true the flag is served
+// from ctxVal; otherwise fv and found describe the value held by the source. A runtime
+// override wins whatever the precedence, as it is set by an operator on purpose.
+func (p *SimpleEnvProvider) resolve(flagKey string, evalCtx openfeature.FlattenedContext) (ctxVal interface{}, fromCtx bool, fv *flagValue, found bool) {
+	if o, ok := p.lookupOverride(p.prefix + strings.ToUpper(flagKey)); ok {
+		return nil, false, o.fv, true
+	}
+
+	var inCtx bool
+	if p.precedence != EnvOnly {
+		ctxVal, inCtx = p.getFromContext(flagKey, evalCtx)
+	}
+	if p.precedence != ContextOnly {
+		fv, found = p.lookup(flagKey)
+	}
+
+	if inCtx && (p.precedence != EnvFirst || !found) {
+		return ctxVal, true, nil, false
+	}
+	return nil, false, fv, found
+}
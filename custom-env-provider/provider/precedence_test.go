@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestPrecedence(t *testing.T) {
+	for name, test := range map[string]struct {
+		source  MapSource
+		opts    []ProviderOption
+		flagKey string
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.BoolResolutionDetail
+	}{
+		"context first uses context": {
+			source:  MapSource{"FT_KILL_SWITCH": "true"},
+			flagKey: "kill_switch",
+			evalCtx: openfeature.FlattenedContext{"kill_switch": false},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonCtx,
+				},
+			},
+		},
+		"env first uses env": {
+			source:  MapSource{"FT_KILL_SWITCH": "true"},
+			opts:    []ProviderOption{WithPrecedence(EnvFirst)},
+			flagKey: "kill_switch",
+			evalCtx: openfeature.FlattenedContext{"kill_switch": false},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"env first falls back to context": {
+			source:  MapSource{},
+			opts:    []ProviderOption{WithPrecedence(EnvFirst)},
+			flagKey: "kill_switch",
+			evalCtx: openfeature.FlattenedContext{"kill_switch": false},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonCtx,
+				},
+			},
+		},
+		"env only ignores context": {
+			source:  MapSource{},
+			opts:    []ProviderOption{WithPrecedence(EnvOnly)},
+			flagKey: "kill_switch",
+			evalCtx: openfeature.FlattenedContext{"kill_switch": false},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_KILL_SWITCH is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"context only ignores env": {
+			source:  MapSource{"FT_KILL_SWITCH": "true"},
+			opts:    []ProviderOption{WithPrecedence(ContextOnly)},
+			flagKey: "kill_switch",
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_KILL_SWITCH is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"context override not allowed": {
+			source:  MapSource{"FT_KILL_SWITCH": "true"},
+			opts:    []ProviderOption{WithContextOverrides("beta_banner")},
+			flagKey: "kill_switch",
+			evalCtx: openfeature.FlattenedContext{"kill_switch": false},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"context override allowed": {
+			source:  MapSource{"FT_BETA_BANNER": "false"},
+			opts:    []ProviderOption{WithContextOverrides("beta_banner")},
+			flagKey: "beta_banner",
+			evalCtx: openfeature.FlattenedContext{"beta_banner": true},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonCtx,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := NewSimpleEnvProvider(append([]ProviderOption{WithSource(test.source)}, test.opts...)...)
+			result := provider.BooleanEvaluation(context.Background(), test.flagKey, true, test.evalCtx)
+
+			opts := []cmp.Option{
+				cmpopts.IgnoreUnexported(openfeature.ResolutionError{}),
+			}
+			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
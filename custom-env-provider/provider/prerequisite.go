@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	// ReasonPrerequisite is reported when a flag serves its default variant
+	// because one of its prerequisites is not met.
+	ReasonPrerequisite = "prerequisite_failed"
+
+	// MetadataPrerequisite is the flag metadata key holding the key of the
+	// prerequisite flag that was not met.
+	MetadataPrerequisite = "prerequisite"
+)
+
+// prerequisite requires another flag to resolve to Value, for the same evaluation
+// context, before a flag definition is evaluated at all, e.g.
+//
+//	"prerequisites":[{"flag":"new_checkout","value":true}]
+type prerequisite struct {
+	Flag  string      `json:"flag"`
+	Value interface{} `json:"value"`
+}
+
+// cycleError reports flags that are each other's prerequisites.
+type cycleError struct {
+	path []string
+}
+
+func (e *cycleError) Error() string {
+	return "prerequisite cycle: " + strings.Join(e.path, " -> ")
+}
+
+// met reports whether the prerequisite holds when evaluating flagKey. Only a cycle
+// is an error; a prerequisite that cannot be evaluated is simply not met.
+func (pr prerequisite) met(flagKey string, evalCtx openfeature.FlattenedContext, sc scope) (bool, error) {
+	path := append(sc.path[:len(sc.path):len(sc.path)], flagKey)
+	for i, k := range path {
+		if strings.EqualFold(k, pr.Flag) {
+			return false, &cycleError{path: append(path[i:], pr.Flag)}
+		}
+	}
+	if sc.valueOf == nil {
+		return false, nil
+	}
+
+	sc.path = path
+	val, err := sc.valueOf(pr.Flag, evalCtx, sc)
+	if err != nil {
+		var cycle *cycleError
+		if errors.As(err, &cycle) {
+			return false, err
+		}
+		return false, nil
+	}
+	return valuesEqual(val, pr.Value), nil
+}
+
+// prerequisiteValue resolves flagKey the way the evaluation methods would, to the
+// value a prerequisite compares against: a context override, the variant a flag
+// document picks, or a plain value decoded as JSON where possible.
+func (p *SimpleEnvProvider) prerequisiteValue(flagKey string, evalCtx openfeature.FlattenedContext, sc scope) (interface{}, error) {
+	ctxVal, fromCtx, fv, found := p.resolve(flagKey, evalCtx)
+	if fromCtx {
+		return ctxVal, nil
+	}
+	if !found {
+		return nil, fmt.Errorf("flag %s is not set", flagKey)
+	}
+	if fv.fileErr != nil {
+		return nil, fv.fileErr
+	}
+	if fv.doc != nil {
+		res, err := fv.doc.evaluate(flagKey, evalCtx, sc)
+		return res.value, err
+	}
+
+	var val interface{}
+	if err := json.Unmarshal([]byte(fv.raw), &val); err != nil {
+		return fv.raw, nil
+	}
+	return val, nil
+}
+
+// validatePrerequisites reports prerequisites on flags that are not set and
+// prerequisite cycles in snap.
+func (p *SimpleEnvProvider) validatePrerequisites(snap *snapshot) []error {
+	graph := make(map[string][]string)
+	for key, fv := range snap.flags {
+		def, ok := fv.doc.(*flagDefinition)
+		if !ok {
+			continue
+		}
+		for _, pr := range def.Prerequisites {
+			graph[key] = append(graph[key], p.prefix+strings.ToUpper(pr.Flag))
+		}
+	}
+
+	keys := make([]string, 0, len(graph))
+	for key := range graph {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		for _, dep := range graph[key] {
+			if _, ok := snap.flags[dep]; !ok {
+				errs = append(errs, fmt.Errorf("%s: prerequisite %s is not set", key, dep))
+			}
+		}
+	}
+
+	// Depth-first search; a flag met again while still on the stack closes a cycle.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int)
+	var stack []string
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch marks[key] {
+		case visiting:
+			for i, k := range stack {
+				if k == key {
+					return &cycleError{path: append(append([]string{}, stack[i:]...), key)}
+				}
+			}
+		case done:
+			return nil
+		}
+		marks[key] = visiting
+		stack = append(stack, key)
+		for _, dep := range graph[key] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		marks[key] = done
+		return nil
+	}
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			errs = append(errs, err)
+			break
+		}
+	}
+	return errs
+}
+
+// validate checks that every prerequisite names a flag.
+func (pr prerequisite) validate() error {
+	if pr.Flag == "" {
+		return errors.New("flag is required")
+	}
+	return nil
+}
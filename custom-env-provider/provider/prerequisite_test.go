@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestPrerequisites(t *testing.T) {
+	const v2 = `{"defaultVariant":"off","variants":{"on":true,"off":false},` +
+		`"prerequisites":[{"flag":"new_checkout","value":true},{"flag":"checkout_region","value":"jp"}],` +
+		`"rules":[{"if":{"attribute":"beta","op":"eq","value":true},"variant":"on"}]}`
+	const v1 = `{"defaultVariant":"off","variants":{"on":true,"off":false},` +
+		`"rules":[{"if":{"attribute":"country","op":"eq","value":"JP"},"variant":"on"}]}`
+	provider := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_NEW_CHECKOUT_V2": v2,
+		"FT_NEW_CHECKOUT":    v1,
+		"FT_CHECKOUT_REGION": "jp",
+	}))
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	for name, test := range map[string]struct {
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.BoolResolutionDetail
+	}{
+		"prerequisites met": {
+			evalCtx: openfeature.FlattenedContext{"country": "JP", "beta": true},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: 0},
+				},
+			},
+		},
+		"parent flag off": {
+			evalCtx: openfeature.FlattenedContext{"country": "US", "beta": true},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       ReasonPrerequisite,
+					Variant:      "off",
+					FlagMetadata: openfeature.FlagMetadata{MetadataPrerequisite: "new_checkout"},
+				},
+			},
+		},
+		"parent flag overridden by context": {
+			evalCtx: openfeature.FlattenedContext{"country": "JP", "beta": true, "checkout_region": "us"},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       ReasonPrerequisite,
+					Variant:      "off",
+					FlagMetadata: openfeature.FlagMetadata{MetadataPrerequisite: "checkout_region"},
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := provider.BooleanEvaluation(context.Background(), "new_checkout_v2", false, test.evalCtx)
+			if diff := cmp.Diff(test.want, result, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrerequisiteCycle(t *testing.T) {
+	doc := func(prerequisite string) string {
+		return `{"defaultVariant":"on","variants":{"on":true},"prerequisites":[{"flag":"` + prerequisite + `","value":true}]}`
+	}
+	provider := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_A":    doc("b"),
+		"FT_B":    doc("c"),
+		"FT_C":    doc("a"),
+		"FT_SELF": doc("self"),
+	}))
+
+	err := provider.Init(openfeature.EvaluationContext{})
+	var initErr *openfeature.ProviderInitError
+	if !errors.As(err, &initErr) || !strings.Contains(initErr.Message, "prerequisite cycle: FT_A -> FT_B -> FT_C -> FT_A") {
+		t.Errorf("Init() error = %v, want a prerequisite cycle", err)
+	}
+
+	for flagKey, wantErr := range map[string]string{
+		"a":    "prerequisite cycle: a -> b -> c -> a",
+		"self": "prerequisite cycle: self -> self",
+	} {
+		result := provider.BooleanEvaluation(context.Background(), flagKey, false, nil)
+		want := openfeature.BoolResolutionDetail{
+			Value: false,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewGeneralResolutionError(wantErr),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+		opts := cmp.Comparer(func(a, b openfeature.ResolutionError) bool { return a.Error() == b.Error() })
+		if diff := cmp.Diff(want, result, opts); diff != "" {
+			t.Errorf("BooleanEvaluation(%s) mismatch (-want +got):\n%s", flagKey, diff)
+		}
+	}
+}
+
+func TestPrerequisiteNotSet(t *testing.T) {
+	provider := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_CHILD": `{"defaultVariant":"off","variants":{"on":true,"off":false},"prerequisites":[{"flag":"parent","value":true}]}`,
+	}))
+
+	err := provider.Init(openfeature.EvaluationContext{})
+	if err == nil || !strings.Contains(err.Error(), "FT_CHILD: prerequisite FT_PARENT is not set") {
+		t.Errorf("Init() error = %v, want the missing prerequisite reported", err)
+	}
+
+	result := provider.BooleanEvaluation(context.Background(), "child", true, nil)
+	if result.Value || result.Reason != ReasonPrerequisite {
+		t.Errorf("BooleanEvaluation() = %v (%s), want false (%s)", result.Value, result.Reason, ReasonPrerequisite)
+	}
+}
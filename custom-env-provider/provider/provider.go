@@ -3,9 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
-	"os"
-	"strconv"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/open-feature/go-sdk/openfeature"
 )
@@ -17,18 +19,46 @@ const (
 )
 
 type SimpleEnvProvider struct {
-	prefix string
+	prefix          string
+	source          Source
+	flagNotFoundErr bool
+	precedence      Precedence
+	overridable     map[string]struct{}
+	pollInterval    time.Duration
+	maxFileSize     int64
+	now             func() time.Time
+	files           fileCache
+	snapshot        atomic.Pointer[snapshot]
+	overrides       atomic.Pointer[map[string]*runtimeOverride]
+	overrideMu      sync.Mutex   // serializes override updates
+	state           atomic.Value // openfeature.State
+	events          chan openfeature.Event
+
+	mu   sync.Mutex
+	done chan struct{} // closed by Shutdown to stop background work
+	wg   sync.WaitGroup
 }
 
 func NewSimpleEnvProvider(opts ...ProviderOption) *SimpleEnvProvider {
 	p := &SimpleEnvProvider{
-		prefix: DefaultPrefix,
+		prefix:          DefaultPrefix,
+		source:          EnvSource{},
+		flagNotFoundErr: true,
+		precedence:      ContextFirst,
+		pollInterval:    DefaultPollInterval,
+		maxFileSize:     DefaultMaxFileSize,
+		now:             time.Now,
+		events:          make(chan openfeature.Event, eventBufferSize),
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
+	// A source that cannot be listed is unusual enough that the error is surfaced
+	// by a later Reload; until then lookups go straight to the source.
+	_ = p.Reload()
+
 	return p
 }
 
@@ -40,6 +70,27 @@ func WithPrefix(prefix string) ProviderOption {
 	}
 }
 
+// WithSource replaces the process environment with src as the place flag values are read from.
+func WithSource(src Source) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.source = src
+	}
+}
+
+// WithLookupFunc is a shorthand for WithSource(SourceFunc(fn)).
+func WithLookupFunc(fn func(key string) (string, bool)) ProviderOption {
+	return WithSource(SourceFunc(fn))
+}
+
+// WithFlagNotFoundError controls how a flag that is not set at all is reported.
+// When enabled (the default) the evaluation fails with FLAG_NOT_FOUND; when disabled
+// the default value is returned with the DEFAULT reason.
+func WithFlagNotFoundError(enabled bool) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.flagNotFoundErr = enabled
+	}
+}
+
 func (p *SimpleEnvProvider) Metadata() openfeature.Metadata {
 	return openfeature.Metadata{
 		Name: "simple-env-flag-evaluator",
@@ -51,9 +102,8 @@ func (p *SimpleEnvProvider) Hooks() []openfeature.Hook {
 }
 
 func (p *SimpleEnvProvider) BooleanEvaluation(ctx context.Context, flagKey string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
-	val := os.Getenv(p.prefix + strings.ToUpper(flagKey))
-
-	if ctxVal, ok := p.getFromContext(flagKey, evalCtx); ok {
+	ctxVal, fromCtx, fv, found := p.resolve(flagKey, evalCtx)
+	if fromCtx {
 		if boolVal, ok := ctxVal.(bool); ok {
 			return openfeature.BoolResolutionDetail{
 				Value: boolVal,
@@ -73,36 +123,76 @@ func (p *SimpleEnvProvider) BooleanEvaluation(ctx context.Context, flagKey strin
 		}
 	}
 
-	if val == "" {
+	if !found {
+		return openfeature.BoolResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: p.flagNotFound(flagKey),
+		}
+	}
+
+	if fv.fileErr != nil {
 		return openfeature.BoolResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason: openfeature.DefaultReason,
+				ResolutionError: openfeature.NewGeneralResolutionError(fv.fileErr.Error()),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	if doc := fv.doc; doc != nil {
+		res, err := doc.evaluate(flagKey, evalCtx, p.scope())
+		if err != nil {
+			return openfeature.BoolResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: resolutionError(err),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+		boolVal, ok := res.value.(bool)
+		if !ok {
+			return openfeature.BoolResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %s of %s is not a boolean", res.variant, flagKey)),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+
+		return openfeature.BoolResolutionDetail{
+			Value: boolVal,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       res.reason,
+				Variant:      res.variant,
+				FlagMetadata: res.metadata,
 			},
 		}
 	}
 
-	boolVal, err := strconv.ParseBool(val)
-	if err != nil {
+	if fv.boolErr != nil {
 		return openfeature.BoolResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
+				ResolutionError: openfeature.NewParseErrorResolutionError(fv.boolErr.Error()),
 				Reason:          openfeature.ErrorReason,
 			},
 		}
 	}
 
 	return openfeature.BoolResolutionDetail{
-		Value: boolVal,
+		Value: fv.boolVal,
 		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason: ReasonEnv,
+			Reason: fv.reason(),
 		},
 	}
 }
 
 func (p *SimpleEnvProvider) StringEvaluation(ctx context.Context, flagKey string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
-	if ctxVal, ok := p.getFromContext(flagKey, evalCtx); ok {
+	ctxVal, fromCtx, fv, found := p.resolve(flagKey, evalCtx)
+	if fromCtx {
 		if strVal, ok := ctxVal.(string); ok {
 			return openfeature.StringResolutionDetail{
 				Value: strVal,
@@ -121,26 +211,66 @@ func (p *SimpleEnvProvider) StringEvaluation(ctx context.Context, flagKey string
 		}
 	}
 
-	val := os.Getenv(p.prefix + strings.ToUpper(flagKey))
-	if val == "" {
+	if !found {
+		return openfeature.StringResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: p.flagNotFound(flagKey),
+		}
+	}
+
+	if fv.fileErr != nil {
 		return openfeature.StringResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason: openfeature.DefaultReason,
+				ResolutionError: openfeature.NewGeneralResolutionError(fv.fileErr.Error()),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	if doc := fv.doc; doc != nil {
+		res, err := doc.evaluate(flagKey, evalCtx, p.scope())
+		if err != nil {
+			return openfeature.StringResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: resolutionError(err),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+		strVal, ok := res.value.(string)
+		if !ok {
+			return openfeature.StringResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %s of %s is not a string", res.variant, flagKey)),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+
+		return openfeature.StringResolutionDetail{
+			Value: strVal,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       res.reason,
+				Variant:      res.variant,
+				FlagMetadata: res.metadata,
 			},
 		}
 	}
 
 	return openfeature.StringResolutionDetail{
-		Value: val,
+		Value: fv.raw,
 		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason: ReasonEnv,
+			Reason: fv.reason(),
 		},
 	}
 }
 
 func (p *SimpleEnvProvider) IntEvaluation(ctx context.Context, flagKey string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
-	if ctxVal, ok := p.getFromContext(flagKey, evalCtx); ok {
+	ctxVal, fromCtx, fv, found := p.resolve(flagKey, evalCtx)
+	if fromCtx {
 		switch v := ctxVal.(type) {
 		case int:
 			return openfeature.IntResolutionDetail{
@@ -174,37 +304,76 @@ func (p *SimpleEnvProvider) IntEvaluation(ctx context.Context, flagKey string, d
 		}
 	}
 
-	val := os.Getenv(p.prefix + strings.ToUpper(flagKey))
-	if val == "" {
+	if !found {
+		return openfeature.IntResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: p.flagNotFound(flagKey),
+		}
+	}
+
+	if fv.fileErr != nil {
 		return openfeature.IntResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason: openfeature.DefaultReason,
+				ResolutionError: openfeature.NewGeneralResolutionError(fv.fileErr.Error()),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	if doc := fv.doc; doc != nil {
+		res, err := doc.evaluate(flagKey, evalCtx, p.scope())
+		if err != nil {
+			return openfeature.IntResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: resolutionError(err),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+		intVal, ok := toInt64(res.value)
+		if !ok {
+			return openfeature.IntResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %s of %s is not an integer", res.variant, flagKey)),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+
+		return openfeature.IntResolutionDetail{
+			Value: intVal,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       res.reason,
+				Variant:      res.variant,
+				FlagMetadata: res.metadata,
 			},
 		}
 	}
 
-	intVal, err := strconv.ParseInt(val, 10, 64)
-	if err != nil {
+	if fv.intErr != nil {
 		return openfeature.IntResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
+				ResolutionError: openfeature.NewParseErrorResolutionError(fv.intErr.Error()),
 				Reason:          openfeature.ErrorReason,
 			},
 		}
 	}
 
 	return openfeature.IntResolutionDetail{
-		Value: intVal,
+		Value: fv.intVal,
 		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason: ReasonEnv,
+			Reason: fv.reason(),
 		},
 	}
 }
 
 func (p *SimpleEnvProvider) FloatEvaluation(ctx context.Context, flagKey string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
-	if ctxVal, ok := p.getFromContext(flagKey, evalCtx); ok {
+	ctxVal, fromCtx, fv, found := p.resolve(flagKey, evalCtx)
+	if fromCtx {
 		switch v := ctxVal.(type) {
 		case float64:
 			return openfeature.FloatResolutionDetail{
@@ -238,50 +407,222 @@ func (p *SimpleEnvProvider) FloatEvaluation(ctx context.Context, flagKey string,
 		}
 	}
 
-	val := os.Getenv(p.prefix + strings.ToUpper(flagKey))
-	if val == "" {
+	if !found {
+		return openfeature.FloatResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: p.flagNotFound(flagKey),
+		}
+	}
+
+	if fv.fileErr != nil {
 		return openfeature.FloatResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				Reason: openfeature.DefaultReason,
+				ResolutionError: openfeature.NewGeneralResolutionError(fv.fileErr.Error()),
+				Reason:          openfeature.ErrorReason,
 			},
 		}
 	}
 
-	floatVal, err := strconv.ParseFloat(val, 64)
-	if err != nil {
+	if doc := fv.doc; doc != nil {
+		res, err := doc.evaluate(flagKey, evalCtx, p.scope())
+		if err != nil {
+			return openfeature.FloatResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: resolutionError(err),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+		floatVal, ok := toFloat64(res.value)
+		if !ok {
+			return openfeature.FloatResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %s of %s is not a number", res.variant, flagKey)),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+
+		return openfeature.FloatResolutionDetail{
+			Value: floatVal,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       res.reason,
+				Variant:      res.variant,
+				FlagMetadata: res.metadata,
+			},
+		}
+	}
+
+	if fv.floatErr != nil {
 		return openfeature.FloatResolutionDetail{
 			Value: defaultValue,
 			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: openfeature.NewParseErrorResolutionError(err.Error()),
+				ResolutionError: openfeature.NewParseErrorResolutionError(fv.floatErr.Error()),
 				Reason:          openfeature.ErrorReason,
 			},
 		}
 	}
 
 	return openfeature.FloatResolutionDetail{
-		Value: floatVal,
+		Value: fv.floatVal,
 		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason: ReasonEnv,
+			Reason: fv.reason(),
 		},
 	}
 }
 
 func (p *SimpleEnvProvider) ObjectEvaluation(ctx context.Context, flagKey string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
-	// not support for object type
+	ctxVal, fromCtx, fv, found := p.resolve(flagKey, evalCtx)
+	if fromCtx {
+		if isObject(ctxVal) {
+			return openfeature.InterfaceResolutionDetail{
+				Value: ctxVal,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonCtx,
+				},
+			}
+		}
+		// If value exists but type is wrong
+		return openfeature.InterfaceResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("context value for %s is not an object", flagKey)),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	if !found {
+		return openfeature.InterfaceResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: p.flagNotFound(flagKey),
+		}
+	}
+
+	if fv.fileErr != nil {
+		return openfeature.InterfaceResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewGeneralResolutionError(fv.fileErr.Error()),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	if doc := fv.doc; doc != nil {
+		res, err := doc.evaluate(flagKey, evalCtx, p.scope())
+		if err != nil {
+			return openfeature.InterfaceResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: resolutionError(err),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+		if !isObject(res.value) {
+			return openfeature.InterfaceResolutionDetail{
+				Value: defaultValue,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %s of %s is not an object", res.variant, flagKey)),
+					Reason:          openfeature.ErrorReason,
+				},
+			}
+		}
+
+		return openfeature.InterfaceResolutionDetail{
+			Value: res.value,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       res.reason,
+				Variant:      res.variant,
+				FlagMetadata: res.metadata,
+			},
+		}
+	}
+
+	if fv.objErr != nil {
+		return openfeature.InterfaceResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewParseErrorResolutionError(fv.objErr.Error()),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	if !isObject(fv.objVal) {
+		return openfeature.InterfaceResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("env value for %s is not a JSON object or array", flagKey)),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
 	return openfeature.InterfaceResolutionDetail{
-		Value: defaultValue,
+		Value: fv.objVal,
 		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			ResolutionError: openfeature.NewGeneralResolutionError("Object type is not supported in simple env provider"),
-			Reason:          openfeature.ErrorReason,
+			Reason: fv.reason(),
 		},
 	}
 }
 
+// lookup returns the parsed value of flagKey: its runtime override if it has one,
+// else the value lookupSource finds for it.
+func (p *SimpleEnvProvider) lookup(flagKey string) (*flagValue, bool) {
+	key := p.prefix + strings.ToUpper(flagKey)
+	if o, ok := p.lookupOverride(key); ok {
+		return o.fv, true
+	}
+	return p.lookupSource(key)
+}
+
+// lookupSource returns the parsed value the source holds for a prefixed key: from
+// the current snapshot when the source can be listed and straight from the source
+// otherwise. A key that is not set is resolved through its _FILE variable. An
+// empty value is a valid, explicitly set value; only a key that is absent reports
+// false.
+func (p *SimpleEnvProvider) lookupSource(key string) (*flagValue, bool) {
+	if snap := p.snapshot.Load(); snap != nil {
+		fv, ok := snap.flags[key]
+		if ok && fv.filePath != "" {
+			return p.readFlagFile(fv.fileKey, fv.filePath), true
+		}
+		return fv, ok
+	}
+
+	raw, ok := p.source.Lookup(key)
+	if !ok {
+		return p.lookupFile(key)
+	}
+	return parseFlagValue(raw), true
+}
+
+func (p *SimpleEnvProvider) flagNotFound(flagKey string) openfeature.ProviderResolutionDetail {
+	if !p.flagNotFoundErr {
+		return openfeature.ProviderResolutionDetail{
+			Reason: openfeature.DefaultReason,
+		}
+	}
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %s is not set", p.prefix+strings.ToUpper(flagKey))),
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
 func (p *SimpleEnvProvider) getFromContext(flagKey string, evalCtx openfeature.FlattenedContext) (interface{}, bool) {
 	if evalCtx == nil {
 		return nil, false
 	}
+	if p.overridable != nil {
+		if _, ok := p.overridable[strings.ToUpper(flagKey)]; !ok {
+			return nil, false
+		}
+	}
 	// First try exact flag key
 	if val, ok := evalCtx[flagKey]; ok {
 		return val, true
@@ -295,3 +636,16 @@ func (p *SimpleEnvProvider) getFromContext(flagKey string, evalCtx openfeature.F
 
 	return nil, false
 }
+
+// isObject reports whether v is a map or a slice, the shapes served by ObjectEvaluation.
+func isObject(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,7 +2,7 @@ package provider
 
 import (
 	"context"
-	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -71,6 +71,52 @@ func TestBooleanEvaluation(t *testing.T) {
 				},
 			},
 		},
+		"variant targeting match with key": {
+			envValue:     `{"defaultVariant":"not-yellow","variants":[{"name":"yellow-with-key","targetingKey":"user","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"yellow","targetingKey":"","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"not-yellow","targetingKey":"","criteria":[{"key":"color","value":"not-yellow"}],"value":false}]}`,
+			flagKey:      "test_flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user",
+				"color":                  "yellow",
+			},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "yellow-with-key",
+				},
+			},
+		},
+		"variant targeting match": {
+			envValue:     `{"defaultVariant":"not-yellow","variants":[{"name":"yellow-with-key","targetingKey":"user","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"yellow","targetingKey":"","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"not-yellow","targetingKey":"","criteria":[{"key":"color","value":"not-yellow"}],"value":false}]}`,
+			flagKey:      "test_flag",
+			defaultValue: false,
+			evalCtx: openfeature.FlattenedContext{
+				"color": "yellow",
+			},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "yellow",
+				},
+			},
+		},
+		"variant default": {
+			envValue:     `{"defaultVariant":"not-yellow","variants":[{"name":"yellow-with-key","targetingKey":"user","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"yellow","targetingKey":"","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"not-yellow","targetingKey":"","criteria":[{"key":"color","value":"not-yellow"}],"value":false}]}`,
+			flagKey:      "test_flag",
+			defaultValue: true,
+			evalCtx: openfeature.FlattenedContext{
+				"color": "blue",
+			},
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "not-yellow",
+				},
+			},
+		},
 		"context value": {
 			flagKey:      "test_flag",
 			defaultValue: false,
@@ -100,12 +146,14 @@ func TestBooleanEvaluation(t *testing.T) {
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := MapSource{}
 			if test.envValue != "" {
-				os.Setenv(DefaultPrefix+test.flagKey, test.envValue)
-				defer os.Unsetenv(DefaultPrefix + test.flagKey)
+				src[DefaultPrefix+strings.ToUpper(test.flagKey)] = test.envValue
 			}
 
-			provider := NewSimpleEnvProvider()
+			provider := NewSimpleEnvProvider(WithSource(src))
 			result := provider.BooleanEvaluation(context.Background(), test.flagKey, test.defaultValue, test.evalCtx)
 
 			opts := []cmp.Option{
@@ -137,6 +185,21 @@ func TestStringEvaluation(t *testing.T) {
 				},
 			},
 		},
+		"variant value type mismatch": {
+			envValue:     `{"defaultVariant":"not-yellow","variants":[{"name":"yellow-with-key","targetingKey":"user","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"yellow","targetingKey":"","criteria":[{"key":"color","value":"yellow"}],"value":true},{"name":"not-yellow","targetingKey":"","criteria":[{"key":"color","value":"not-yellow"}],"value":false}]}`,
+			flagKey:      "test_flag",
+			defaultValue: "default",
+			evalCtx: openfeature.FlattenedContext{
+				"color": "yellow",
+			},
+			want: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError("variant yellow of test_flag is not a string"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
 		"context value": {
 			flagKey:      "test_flag",
 			defaultValue: "default",
@@ -166,16 +229,20 @@ func TestStringEvaluation(t *testing.T) {
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := MapSource{}
 			if test.envValue != "" {
-				os.Setenv(DefaultPrefix+test.flagKey, test.envValue)
-				defer os.Unsetenv(DefaultPrefix + test.flagKey)
+				src[DefaultPrefix+strings.ToUpper(test.flagKey)] = test.envValue
 			}
 
-			provider := NewSimpleEnvProvider()
+			provider := NewSimpleEnvProvider(WithSource(src))
 			result := provider.StringEvaluation(context.Background(), test.flagKey, test.defaultValue, test.evalCtx)
 
 			opts := []cmp.Option{
-				cmpopts.IgnoreUnexported(openfeature.ResolutionError{}),
+				cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+					return a.Error() == b.Error()
+				}),
 			}
 			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
 				t.Errorf("result mismatch (-want +got):\n%s", diff)
@@ -203,6 +270,33 @@ func TestIntEvaluation(t *testing.T) {
 				},
 			},
 		},
+		"variant value": {
+			envValue:     `{"defaultVariant":"low","variants":[{"name":"high","criteria":[{"key":"plan","value":"pro"}],"value":1000},{"name":"low","criteria":[{"key":"plan","value":"free"}],"value":10}]}`,
+			flagKey:      "test_flag",
+			defaultValue: 0,
+			evalCtx: openfeature.FlattenedContext{
+				"plan": "pro",
+			},
+			want: openfeature.IntResolutionDetail{
+				Value: 1000,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "high",
+				},
+			},
+		},
+		"variant undefined default": {
+			envValue:     `{"defaultVariant":"missing","variants":[{"name":"high","criteria":[{"key":"plan","value":"pro"}],"value":1000}]}`,
+			flagKey:      "test_flag",
+			defaultValue: 5,
+			want: openfeature.IntResolutionDetail{
+				Value: 5,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError("default variant \"missing\" is not defined"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
 		"context value int": {
 			flagKey:      "test_flag",
 			defaultValue: 0,
@@ -257,16 +351,20 @@ func TestIntEvaluation(t *testing.T) {
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := MapSource{}
 			if test.envValue != "" {
-				os.Setenv(DefaultPrefix+test.flagKey, test.envValue)
-				defer os.Unsetenv(DefaultPrefix + test.flagKey)
+				src[DefaultPrefix+strings.ToUpper(test.flagKey)] = test.envValue
 			}
 
-			provider := NewSimpleEnvProvider()
+			provider := NewSimpleEnvProvider(WithSource(src))
 			result := provider.IntEvaluation(context.Background(), test.flagKey, test.defaultValue, test.evalCtx)
 
 			opts := []cmp.Option{
-				cmpopts.IgnoreUnexported(openfeature.ResolutionError{}),
+				cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+					return a.Error() == b.Error()
+				}),
 			}
 			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
 				t.Errorf("result mismatch (-want +got):\n%s", diff)
@@ -348,12 +446,14 @@ func TestFloatEvaluation(t *testing.T) {
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := MapSource{}
 			if test.envValue != "" {
-				os.Setenv(DefaultPrefix+test.flagKey, test.envValue)
-				defer os.Unsetenv(DefaultPrefix + test.flagKey)
+				src[DefaultPrefix+strings.ToUpper(test.flagKey)] = test.envValue
 			}
 
-			provider := NewSimpleEnvProvider()
+			provider := NewSimpleEnvProvider(WithSource(src))
 			result := provider.FloatEvaluation(context.Background(), test.flagKey, test.defaultValue, test.evalCtx)
 
 			opts := []cmp.Option{
@@ -368,29 +468,191 @@ func TestFloatEvaluation(t *testing.T) {
 
 func TestObjectEvaluation(t *testing.T) {
 	for name, test := range map[string]struct {
+		envValue     string
 		flagKey      string
 		defaultValue interface{}
 		evalCtx      openfeature.FlattenedContext
 		want         openfeature.InterfaceResolutionDetail
 	}{
-		"object evaluation not supported": {
+		"environment value object": {
+			envValue:     `{"limit":100,"burst":[1,2]}`,
+			flagKey:      "test_flag",
+			defaultValue: map[string]interface{}{"key": "value"},
+			want: openfeature.InterfaceResolutionDetail{
+				Value: map[string]interface{}{"limit": float64(100), "burst": []interface{}{float64(1), float64(2)}},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"environment value array": {
+			envValue:     `["alice","bob"]`,
+			flagKey:      "test_flag",
+			defaultValue: nil,
+			want: openfeature.InterfaceResolutionDetail{
+				Value: []interface{}{"alice", "bob"},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"variant value": {
+			envValue:     `{"defaultVariant":"default","variants":[{"name":"default","criteria":[],"value":{"limit":10}}]}`,
+			flagKey:      "test_flag",
+			defaultValue: nil,
+			want: openfeature.InterfaceResolutionDetail{
+				Value: map[string]interface{}{"limit": float64(10)},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "default",
+				},
+			},
+		},
+		"no value": {
 			flagKey:      "test_flag",
 			defaultValue: map[string]interface{}{"key": "value"},
 			want: openfeature.InterfaceResolutionDetail{
 				Value: map[string]interface{}{"key": "value"},
 				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-					ResolutionError: openfeature.NewGeneralResolutionError("Object type is not supported in simple env provider"),
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_TEST_FLAG is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"invalid environment value": {
+			envValue:     `{"limit":`,
+			flagKey:      "test_flag",
+			defaultValue: map[string]interface{}{"key": "value"},
+			want: openfeature.InterfaceResolutionDetail{
+				Value: map[string]interface{}{"key": "value"},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError("unexpected end of JSON input"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"scalar environment value": {
+			envValue:     "42",
+			flagKey:      "test_flag",
+			defaultValue: map[string]interface{}{"key": "value"},
+			want: openfeature.InterfaceResolutionDetail{
+				Value: map[string]interface{}{"key": "value"},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError("env value for test_flag is not a JSON object or array"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"context value map": {
+			flagKey:      "test_flag",
+			defaultValue: nil,
+			evalCtx: openfeature.FlattenedContext{
+				"test_flag": map[string]interface{}{"key": "context"},
+			},
+			want: openfeature.InterfaceResolutionDetail{
+				Value: map[string]interface{}{"key": "context"},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonCtx,
+				},
+			},
+		},
+		"context value slice": {
+			flagKey:      "test_flag",
+			defaultValue: nil,
+			evalCtx: openfeature.FlattenedContext{
+				"test_flag": []string{"a", "b"},
+			},
+			want: openfeature.InterfaceResolutionDetail{
+				Value: []string{"a", "b"},
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonCtx,
+				},
+			},
+		},
+		"invalid context value type": {
+			flagKey:      "test_flag",
+			defaultValue: nil,
+			evalCtx: openfeature.FlattenedContext{
+				"test_flag": "not an object",
+			},
+			want: openfeature.InterfaceResolutionDetail{
+				Value: nil,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError("context value for test_flag is not an object"),
 					Reason:          openfeature.ErrorReason,
 				},
 			},
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
-			provider := NewSimpleEnvProvider()
+			t.Parallel()
+
+			src := MapSource{}
+			if test.envValue != "" {
+				src[DefaultPrefix+strings.ToUpper(test.flagKey)] = test.envValue
+			}
+
+			provider := NewSimpleEnvProvider(WithSource(src))
 			result := provider.ObjectEvaluation(context.Background(), test.flagKey, test.defaultValue, test.evalCtx)
 
 			opts := []cmp.Option{
-				cmpopts.IgnoreUnexported(openfeature.ResolutionError{}),
+				cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+					return a.Error() == b.Error()
+				}),
+			}
+			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMissingAndEmptyValues(t *testing.T) {
+	for name, test := range map[string]struct {
+		source Source
+		opts   []ProviderOption
+		want   openfeature.StringResolutionDetail
+	}{
+		"explicitly empty value": {
+			source: MapSource{"FT_BANNER": ""},
+			want: openfeature.StringResolutionDetail{
+				Value: "",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"unset value": {
+			source: MapSource{},
+			want: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_BANNER is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"unset value without flag not found error": {
+			source: MapSource{},
+			opts:   []ProviderOption{WithFlagNotFoundError(false)},
+			want: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: openfeature.DefaultReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := NewSimpleEnvProvider(append([]ProviderOption{WithSource(test.source)}, test.opts...)...)
+			result := provider.StringEvaluation(context.Background(), "banner", "default", nil)
+
+			opts := []cmp.Option{
+				cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+					return a.Error() == b.Error()
+				}),
 			}
 			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
 				t.Errorf("result mismatch (-want +got):\n%s", diff)
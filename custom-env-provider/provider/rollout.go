@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// rollout splits evaluation contexts between variants by percentage. The split is
+// deterministic: a targeting key always lands in the same bucket of a given flag.
+type rollout []bucket
+
+type bucket struct {
+	Variant    string  `json:"variant"`
+	Percentage float64 `json:"percentage"`
+}
+
+// pick returns the variant of the bucket that flagKey and targetingKey hash into.
+func (r rollout) pick(flagKey, targetingKey string) string {
+	h := fnv.New32a()
+	h.Write([]byte(flagKey + "/" + targetingKey))
+	point := float64(h.Sum32()) / (math.MaxUint32 + 1) * 100
+
+	var upper float64
+	for _, b := range r {
+		upper += b.Percentage
+		if point < upper {
+			return b.Variant
+		}
+	}
+	// Guard against percentages that sum to slightly less than 100 through rounding.
+	return r[len(r)-1].Variant
+}
+
+// validate checks that every bucket refers to a variant and that the buckets cover 100%.
+func (r rollout) validate(variants map[string]interface{}) error {
+	var total float64
+	for _, b := range r {
+		if _, ok := variants[b.Variant]; !ok {
+			return fmt.Errorf("variant %q is not defined", b.Variant)
+		}
+		if b.Percentage < 0 {
+			return fmt.Errorf("variant %q has a negative percentage", b.Variant)
+		}
+		total += b.Percentage
+	}
+	if math.Abs(total-100) > 1e-9 {
+		return fmt.Errorf("percentages add up to %g, want 100", total)
+	}
+	return nil
+}
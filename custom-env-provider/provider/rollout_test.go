@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestRolloutPick(t *testing.T) {
+	t.Parallel()
+
+	r := rollout{{Variant: "on", Percentage: 10}, {Variant: "off", Percentage: 90}}
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		got := r.pick("new_checkout", key)
+		if again := r.pick("new_checkout", key); again != got {
+			t.Fatalf("pick(%s) is not stable: %s then %s", key, got, again)
+		}
+		counts[got]++
+	}
+
+	if on := counts["on"]; on < 800 || on > 1200 {
+		t.Errorf("%d of 10000 users got on, want about 1000", on)
+	}
+}
+
+func TestRolloutValidate(t *testing.T) {
+	variants := map[string]interface{}{"on": true, "off": false}
+
+	for name, test := range map[string]struct {
+		rollout rollout
+		wantErr bool
+	}{
+		"valid": {
+			rollout: rollout{{Variant: "on", Percentage: 12.5}, {Variant: "off", Percentage: 87.5}},
+		},
+		"undefined variant": {
+			rollout: rollout{{Variant: "maybe", Percentage: 100}},
+			wantErr: true,
+		},
+		"under 100": {
+			rollout: rollout{{Variant: "on", Percentage: 10}, {Variant: "off", Percentage: 80}},
+			wantErr: true,
+		},
+		"negative": {
+			rollout: rollout{{Variant: "on", Percentage: -10}, {Variant: "off", Percentage: 110}},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := test.rollout.validate(variants); (err != nil) != test.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRolloutEvaluation(t *testing.T) {
+	const doc = `{"defaultVariant":"off","variants":{"on":true,"off":false},"rollout":[{"variant":"on","percentage":50},{"variant":"off","percentage":50}]}`
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_NEW_CHECKOUT": doc}))
+
+	r := rollout{{Variant: "on", Percentage: 50}, {Variant: "off", Percentage: 50}}
+	variant := r.pick("new_checkout", "user-1")
+
+	for name, test := range map[string]struct {
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.BoolResolutionDetail
+	}{
+		"targeting key": {
+			evalCtx: openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"},
+			want: openfeature.BoolResolutionDetail{
+				Value: variant == "on",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.SplitReason,
+					Variant: variant,
+				},
+			},
+		},
+		"missing targeting key": {
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTargetingKeyMissingResolutionError("flag new_checkout is rolled out by targeting key"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result := provider.BooleanEvaluation(context.Background(), "new_checkout", false, test.evalCtx)
+
+			opts := []cmp.Option{
+				cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+					return a.Error() == b.Error()
+				}),
+			}
+			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/open-feature/go-sdk/openfeature"
+	"golang.org/x/mod/semver"
+)
+
+// rule serves Variant when its condition holds for the evaluation context. The
+// condition is either structured (if) or a Common Expression Language expression
+// over the context attributes (cel), e.g.
+//
+//	{"cel":"user.country == 'JP' && version >= 3","variant":"on"}
+type rule struct {
+	If      *condition `json:"if,omitempty"`
+	CEL     string     `json:"cel,omitempty"`
+	Variant string     `json:"variant"`
+
+	prg cel.Program
+}
+
+func (r *rule) compile() error {
+	switch {
+	case r.If != nil && r.CEL != "":
+		return errors.New("rule must have only one of if or cel")
+	case r.If != nil:
+		return r.If.compile()
+	case r.CEL != "":
+		prg, err := compileCEL(r.CEL)
+		if err != nil {
+			return err
+		}
+		r.prg = prg
+		return nil
+	default:
+		return errors.New("rule must have one of if or cel")
+	}
+}
+
+func (r *rule) matches(evalCtx openfeature.FlattenedContext) bool {
+	if r.prg != nil {
+		return evalCEL(r.prg, evalCtx)
+	}
+	return r.If.eval(evalCtx)
+}
+
+// condition is either a composition of other conditions (and, or, not) or a
+// comparison of a single context attribute, e.g.
+//
+//	{"and":[{"attribute":"country","op":"in","value":["JP","US"]},{"not":{"attribute":"email","op":"ends_with","value":"@example.com"}}]}
+//
+// Supported operators are eq, neq, in, not_in, starts_with, ends_with, matches
+// (a regular expression), lt, lte, gt, gte (numbers) and semver_eq, semver_lt,
+// semver_lte, semver_gt, semver_gte. A comparison against a missing attribute or
+// an attribute of the wrong type does not hold.
+type condition struct {
+	And []condition `json:"and,omitempty"`
+	Or  []condition `json:"or,omitempty"`
+	Not *condition  `json:"not,omitempty"`
+
+	Attribute string      `json:"attribute,omitempty"`
+	Op        string      `json:"op,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// compile checks that the condition and those nested in it have exactly one form
+// and a value their operator accepts, and compiles matches patterns once so that
+// evaluations reuse them.
+func (c *condition) compile() error {
+	forms := 0
+	if c.And != nil {
+		forms++
+	}
+	if c.Or != nil {
+		forms++
+	}
+	if c.Not != nil {
+		forms++
+	}
+	if c.Op != "" {
+		forms++
+	}
+	if forms != 1 {
+		return errors.New("condition must have exactly one of and, or, not or op")
+	}
+
+	for i := range c.And {
+		if err := c.And[i].compile(); err != nil {
+			return fmt.Errorf("and[%d]: %w", i, err)
+		}
+	}
+	for i := range c.Or {
+		if err := c.Or[i].compile(); err != nil {
+			return fmt.Errorf("or[%d]: %w", i, err)
+		}
+	}
+	if c.Not != nil {
+		if err := c.Not.compile(); err != nil {
+			return fmt.Errorf("not: %w", err)
+		}
+	}
+	if c.Op == "" {
+		return nil
+	}
+
+	if c.Attribute == "" {
+		return fmt.Errorf("%s: attribute is required", c.Op)
+	}
+	switch c.Op {
+	case "eq", "neq":
+	case "in", "not_in":
+		if _, ok := c.Value.([]interface{}); !ok {
+			return fmt.Errorf("%s: value must be an array", c.Op)
+		}
+	case "starts_with", "ends_with":
+		if _, ok := c.Value.(string); !ok {
+			return fmt.Errorf("%s: value must be a string", c.Op)
+		}
+	case "matches":
+		pattern, ok := c.Value.(string)
+		if !ok {
+			return fmt.Errorf("%s: value must be a string", c.Op)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Op, err)
+		}
+		c.re = re
+	case "lt", "lte", "gt", "gte":
+		if _, ok := toFloat64(c.Value); !ok {
+			return fmt.Errorf("%s: value must be a number", c.Op)
+		}
+	case "semver_eq", "semver_lt", "semver_lte", "semver_gt", "semver_gte":
+		v, ok := c.Value.(string)
+		if !ok || !semver.IsValid(canonicalSemver(v)) {
+			return fmt.Errorf("%s: value must be a semantic version", c.Op)
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", c.Op)
+	}
+	return nil
+}
+
+func (c *condition) eval(evalCtx openfeature.FlattenedContext) bool {
+	switch {
+	case c.And != nil:
+		for i := range c.And {
+			if !c.And[i].eval(evalCtx) {
+				return false
+			}
+		}
+		return true
+	case c.Or != nil:
+		for i := range c.Or {
+			if c.Or[i].eval(evalCtx) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.eval(evalCtx)
+	}
+
+	attr, ok := evalCtx[c.Attribute]
+	if !ok {
+		return false
+	}
+
+	switch c.Op {
+	case "eq":
+		return valuesEqual(attr, c.Value)
+	case "neq":
+		return !valuesEqual(attr, c.Value)
+	case "in", "not_in":
+		found := false
+		for _, v := range c.Value.([]interface{}) {
+			if valuesEqual(attr, v) {
+				found = true
+				break
+			}
+		}
+		return found == (c.Op == "in")
+	case "starts_with":
+		s, ok := attr.(string)
+		return ok && strings.HasPrefix(s, c.Value.(string))
+	case "ends_with":
+		s, ok := attr.(string)
+		return ok && strings.HasSuffix(s, c.Value.(string))
+	case "matches":
+		s, ok := attr.(string)
+		return ok && c.re.MatchString(s)
+	case "lt", "lte", "gt", "gte":
+		a, ok := toFloat64(attr)
+		if !ok {
+			return false
+		}
+		b, _ := toFloat64(c.Value)
+		return compareHolds(c.Op, cmpFloat(a, b))
+	case "semver_eq", "semver_lt", "semver_lte", "semver_gt", "semver_gte":
+		s, ok := attr.(string)
+		if !ok || !semver.IsValid(canonicalSemver(s)) {
+			return false
+		}
+		cmp := semver.Compare(canonicalSemver(s), canonicalSemver(c.Value.(string)))
+		return compareHolds(strings.TrimPrefix(c.Op, "semver_"), cmp)
+	}
+	return false
+}
+
+// compareHolds reports whether the result of a three-way comparison satisfies op.
+func compareHolds(op string, cmp int) bool {
+	switch op {
+	case "eq":
+		return cmp == 0
+	case "lt":
+		return cmp < 0
+	case "lte":
+		return cmp <= 0
+	case "gt":
+		return cmp > 0
+	case "gte":
+		return cmp >= 0
+	}
+	return false
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// canonicalSemver adds the "v" prefix golang.org/x/mod/semver expects.
+func canonicalSemver(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestConditionEval(t *testing.T) {
+	evalCtx := openfeature.FlattenedContext{
+		"country": "JP",
+		"email":   "dev@example.com",
+		"age":     30,
+		"version": "1.4.2",
+		"beta":    true,
+	}
+
+	for name, test := range map[string]struct {
+		cond string
+		want bool
+	}{
+		"eq":                      {cond: `{"attribute":"country","op":"eq","value":"JP"}`, want: true},
+		"eq number":               {cond: `{"attribute":"age","op":"eq","value":30}`, want: true},
+		"neq":                     {cond: `{"attribute":"country","op":"neq","value":"JP"}`, want: false},
+		"in":                      {cond: `{"attribute":"country","op":"in","value":["US","JP"]}`, want: true},
+		"not_in":                  {cond: `{"attribute":"country","op":"not_in","value":["US","JP"]}`, want: false},
+		"starts_with":             {cond: `{"attribute":"email","op":"starts_with","value":"dev@"}`, want: true},
+		"ends_with":               {cond: `{"attribute":"email","op":"ends_with","value":"@example.org"}`, want: false},
+		"matches":                 {cond: `{"attribute":"email","op":"matches","value":"^[a-z]+@example\\.com$"}`, want: true},
+		"lt":                      {cond: `{"attribute":"age","op":"lt","value":30}`, want: false},
+		"lte":                     {cond: `{"attribute":"age","op":"lte","value":30}`, want: true},
+		"gt":                      {cond: `{"attribute":"age","op":"gt","value":18}`, want: true},
+		"gte":                     {cond: `{"attribute":"age","op":"gte","value":31}`, want: false},
+		"semver_gte":              {cond: `{"attribute":"version","op":"semver_gte","value":"1.4.0"}`, want: true},
+		"semver_lt":               {cond: `{"attribute":"version","op":"semver_lt","value":"v1.4.2"}`, want: false},
+		"semver_eq":               {cond: `{"attribute":"version","op":"semver_eq","value":"1.4.2"}`, want: true},
+		"missing attribute":       {cond: `{"attribute":"plan","op":"neq","value":"pro"}`, want: false},
+		"wrong attribute type":    {cond: `{"attribute":"age","op":"starts_with","value":"3"}`, want: false},
+		"and":                     {cond: `{"and":[{"attribute":"country","op":"eq","value":"JP"},{"attribute":"beta","op":"eq","value":true}]}`, want: true},
+		"or":                      {cond: `{"or":[{"attribute":"country","op":"eq","value":"US"},{"attribute":"age","op":"gt","value":65}]}`, want: false},
+		"not":                     {cond: `{"not":{"attribute":"country","op":"eq","value":"US"}}`, want: true},
+		"nested":                  {cond: `{"and":[{"or":[{"attribute":"country","op":"eq","value":"US"},{"attribute":"country","op":"eq","value":"JP"}]},{"not":{"attribute":"email","op":"ends_with","value":"@example.org"}}]}`, want: true},
+		"targeting key attribute": {cond: `{"attribute":"targetingKey","op":"eq","value":"user-1"}`, want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var c condition
+			if err := json.Unmarshal([]byte(test.cond), &c); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.compile(); err != nil {
+				t.Fatalf("compile() error = %v", err)
+			}
+			if got := c.eval(evalCtx); got != test.want {
+				t.Errorf("eval() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConditionCompile(t *testing.T) {
+	for name, cond := range map[string]string{
+		"no form":           `{}`,
+		"two forms":         `{"and":[],"attribute":"a","op":"eq","value":1}`,
+		"unknown operator":  `{"attribute":"a","op":"like","value":"x"}`,
+		"missing attribute": `{"op":"eq","value":"x"}`,
+		"in without array":  `{"attribute":"a","op":"in","value":"x"}`,
+		"invalid regex":     `{"attribute":"a","op":"matches","value":"("}`,
+		"invalid number":    `{"attribute":"a","op":"gt","value":"x"}`,
+		"invalid semver":    `{"attribute":"a","op":"semver_gt","value":"latest"}`,
+		"nested error":      `{"not":{"attribute":"a","op":"like","value":"x"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var c condition
+			if err := json.Unmarshal([]byte(cond), &c); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.compile(); err == nil {
+				t.Errorf("compile(%s) succeeded, want error", cond)
+			}
+		})
+	}
+}
+
+func TestRulesEvaluation(t *testing.T) {
+	const doc = `{"defaultVariant":"small","variants":{"small":10,"large":1000},"rules":[` +
+		`{"if":{"attribute":"plan","op":"eq","value":"free"},"variant":"small"},` +
+		`{"if":{"attribute":"plan","op":"in","value":["pro","enterprise"]},"variant":"large"}]}`
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_RATE_LIMIT": doc, "FT_BROKEN": `{"defaultVariant":"a","variants":{"a":1},"rules":[{"if":{"attribute":"x","op":"like"},"variant":"a"}]}`}))
+
+	for name, test := range map[string]struct {
+		flagKey string
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.IntResolutionDetail
+	}{
+		"second rule matches": {
+			flagKey: "rate_limit",
+			evalCtx: openfeature.FlattenedContext{"plan": "enterprise"},
+			want: openfeature.IntResolutionDetail{
+				Value: 1000,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "large",
+					FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: 1},
+				},
+			},
+		},
+		"no rule matches": {
+			flagKey: "rate_limit",
+			evalCtx: openfeature.FlattenedContext{"plan": "trial"},
+			want: openfeature.IntResolutionDetail{
+				Value: 10,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "small",
+				},
+			},
+		},
+		"broken rule": {
+			flagKey: "broken",
+			want: openfeature.IntResolutionDetail{
+				Value: 0,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewParseErrorResolutionError(`rules[0]: unknown operator "like"`),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result := provider.IntEvaluation(context.Background(), test.flagKey, 0, test.evalCtx)
+
+			opts := []cmp.Option{
+				cmpopts.IgnoreUnexported(openfeature.ResolutionError{}),
+			}
+			if diff := cmp.Diff(test.want, result, opts...); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err == nil {
+		t.Error("Init() succeeded with a broken rule")
+	}
+}
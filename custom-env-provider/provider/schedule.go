@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// ReasonSchedule is reported when a schedule window picked the variant.
+	ReasonSchedule = "schedule"
+
+	// MetadataScheduleIndex is the flag metadata key holding the index of the
+	// schedule window that picked the variant.
+	MetadataScheduleIndex = "scheduleIndex"
+)
+
+// WithClock replaces time.Now as the source of the current time for scheduled
+// flags, so that tests can control it.
+func WithClock(now func() time.Time) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.now = now
+	}
+}
+
+// schedule is a list of time windows, each serving a variant while it is open.
+// The first open window wins. A window is bounded by an optional start and end
+// (RFC 3339, end exclusive) and optionally recurs on some days of the week
+// and/or between two times of day, e.g. a launch at 09:00 JST and a weekend
+// campaign that ends with March:
+//
+//	"schedule":[
+//	  {"variant":"on","start":"2026-04-01T09:00:00+09:00"},
+//	  {"variant":"sale","end":"2026-04-01T00:00:00+09:00","days":["sat","sun"],"from":"10:00","to":"18:00","timezone":"Asia/Tokyo"}
+//	]
+//
+// Times of day are in timezone, UTC by default. A window whose "to" is earlier
+// than its "from" runs past midnight into the next day.
+type schedule []window
+
+type window struct {
+	Variant  string   `json:"variant"`
+	Start    string   `json:"start,omitempty"`
+	End      string   `json:"end,omitempty"`
+	Days     []string `json:"days,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       string   `json:"to,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+
+	start, end time.Time
+	days       map[time.Weekday]bool
+	daily      bool
+	from, to   time.Duration // since midnight
+	loc        *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// compile parses the timestamps, days and times of day of every window.
+func (s schedule) compile() error {
+	for i := range s {
+		if err := s[i].compile(); err != nil {
+			return fmt.Errorf("schedule[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (w *window) compile() error {
+	var err error
+	if w.Start != "" {
+		if w.start, err = time.Parse(time.RFC3339, w.Start); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+	}
+	if w.End != "" {
+		if w.end, err = time.Parse(time.RFC3339, w.End); err != nil {
+			return fmt.Errorf("end: %w", err)
+		}
+	}
+	if !w.start.IsZero() && !w.end.IsZero() && !w.start.Before(w.end) {
+		return errors.New("start must be before end")
+	}
+
+	w.loc = time.UTC
+	if w.Timezone != "" {
+		if w.loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+
+	if len(w.Days) > 0 {
+		w.days = make(map[time.Weekday]bool, len(w.Days))
+		for _, d := range w.Days {
+			day, ok := weekdays[strings.ToLower(d)]
+			if !ok {
+				return fmt.Errorf("unknown day %q", d)
+			}
+			w.days[day] = true
+		}
+	}
+
+	if (w.From == "") != (w.To == "") {
+		return errors.New("from and to must be set together")
+	}
+	if w.From != "" {
+		if w.from, err = timeOfDay(w.From); err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+		if w.to, err = timeOfDay(w.To); err != nil {
+			return fmt.Errorf("to: %w", err)
+		}
+		if w.from == w.to {
+			return errors.New("from and to must differ")
+		}
+		w.daily = true
+	}
+	return nil
+}
+
+// timeOfDay parses "15:04" into the duration since midnight.
+func timeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// pick returns the index of the first window open at now, or -1.
+func (s schedule) pick(now time.Time) int {
+	for i := range s {
+		if s[i].open(now) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *window) open(now time.Time) bool {
+	if !w.start.IsZero() && now.Before(w.start) {
+		return false
+	}
+	if !w.end.IsZero() && !now.Before(w.end) {
+		return false
+	}
+
+	local := now.In(w.loc)
+	if !w.daily {
+		return w.onDay(local.Weekday())
+	}
+
+	// Wall-clock time, not time elapsed since midnight, which differs on DST days.
+	sinceMidnight := time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+	if w.from < w.to {
+		return w.onDay(local.Weekday()) && sinceMidnight >= w.from && sinceMidnight < w.to
+	}
+	// The window runs past midnight: it is open late on a listed day and early on
+	// the day after one.
+	return (w.onDay(local.Weekday()) && sinceMidnight >= w.from) ||
+		(w.onDay((local.Weekday()+6)%7) && sinceMidnight < w.to)
+}
+
+func (w *window) onDay(day time.Weekday) bool {
+	return w.days == nil || w.days[day]
+}
+
+// validate checks that every window refers to a variant.
+func (s schedule) validate(variants map[string]interface{}) error {
+	for i, w := range s {
+		if _, ok := variants[w.Variant]; !ok {
+			return fmt.Errorf("schedule[%d]: variant %q is not defined", i, w.Variant)
+		}
+	}
+	return nil
+}
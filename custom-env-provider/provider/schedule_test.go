@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestWindowOpen(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	for name, test := range map[string]struct {
+		window string
+		now    time.Time
+		want   bool
+	}{
+		"before start": {
+			window: `{"start":"2026-04-01T09:00:00+09:00"}`,
+			now:    time.Date(2026, 4, 1, 8, 59, 59, 0, jst),
+			want:   false,
+		},
+		"at start": {
+			window: `{"start":"2026-04-01T09:00:00+09:00"}`,
+			now:    time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		"at end": {
+			window: `{"end":"2026-04-01T00:00:00Z"}`,
+			now:    time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			want:   false,
+		},
+		"between start and end": {
+			window: `{"start":"2026-03-01T00:00:00Z","end":"2026-04-01T00:00:00Z"}`,
+			now:    time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		"listed day": {
+			window: `{"days":["sat","sun"]}`,
+			now:    time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC), // Saturday
+			want:   true,
+		},
+		"other day": {
+			window: `{"days":["sat","sun"]}`,
+			now:    time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC), // Monday
+			want:   false,
+		},
+		"day in timezone": {
+			window: `{"days":["sat"],"timezone":"Asia/Tokyo"}`,
+			now:    time.Date(2026, 3, 13, 15, 0, 0, 0, time.UTC), // Saturday 00:00 in Tokyo
+			want:   true,
+		},
+		"inside daily window": {
+			window: `{"from":"09:00","to":"18:00","timezone":"Asia/Tokyo"}`,
+			now:    time.Date(2026, 3, 16, 9, 0, 0, 0, jst),
+			want:   true,
+		},
+		"after daily window": {
+			window: `{"from":"09:00","to":"18:00","timezone":"Asia/Tokyo"}`,
+			now:    time.Date(2026, 3, 16, 18, 0, 0, 0, jst),
+			want:   false,
+		},
+		"overnight window late": {
+			window: `{"days":["fri"],"from":"22:00","to":"02:00"}`,
+			now:    time.Date(2026, 3, 13, 23, 0, 0, 0, time.UTC), // Friday
+			want:   true,
+		},
+		"overnight window early next day": {
+			window: `{"days":["fri"],"from":"22:00","to":"02:00"}`,
+			now:    time.Date(2026, 3, 14, 1, 0, 0, 0, time.UTC), // Saturday
+			want:   true,
+		},
+		"overnight window early on listed day": {
+			window: `{"days":["fri"],"from":"22:00","to":"02:00"}`,
+			now:    time.Date(2026, 3, 13, 1, 0, 0, 0, time.UTC), // Friday
+			want:   false,
+		},
+		"daily window on DST start": {
+			window: `{"from":"09:00","to":"10:00","timezone":"America/New_York"}`,
+			now:    time.Date(2026, 3, 8, 13, 30, 0, 0, time.UTC), // 09:30 EDT
+			want:   true,
+		},
+		"after daily window on DST start": {
+			window: `{"from":"09:00","to":"10:00","timezone":"America/New_York"}`,
+			now:    time.Date(2026, 3, 8, 14, 30, 0, 0, time.UTC), // 10:30 EDT
+			want:   false,
+		},
+		"daily window on DST end": {
+			window: `{"from":"09:00","to":"10:00","timezone":"America/New_York"}`,
+			now:    time.Date(2026, 11, 1, 14, 30, 0, 0, time.UTC), // 09:30 EST
+			want:   true,
+		},
+		"recurring window after end": {
+			window: `{"end":"2026-04-01T00:00:00Z","days":["sat","sun"],"from":"10:00","to":"18:00"}`,
+			now:    time.Date(2026, 4, 4, 12, 0, 0, 0, time.UTC), // Saturday
+			want:   false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var w window
+			if err := json.Unmarshal([]byte(test.window), &w); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.compile(); err != nil {
+				t.Fatalf("compile() error = %v", err)
+			}
+			if got := w.open(test.now); got != test.want {
+				t.Errorf("open(%s) = %v, want %v", test.now, got, test.want)
+			}
+		})
+	}
+}
+
+func TestWindowCompile(t *testing.T) {
+	for name, w := range map[string]string{
+		"invalid start":   `{"start":"tomorrow"}`,
+		"end before":      `{"start":"2026-04-01T00:00:00Z","end":"2026-03-01T00:00:00Z"}`,
+		"unknown day":     `{"days":["someday"]}`,
+		"from without to": `{"from":"09:00"}`,
+		"invalid time":    `{"from":"9am","to":"18:00"}`,
+		"empty window":    `{"from":"09:00","to":"09:00"}`,
+		"bad timezone":    `{"timezone":"Mars/Olympus"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var win window
+			if err := json.Unmarshal([]byte(w), &win); err != nil {
+				t.Fatal(err)
+			}
+			if err := win.compile(); err == nil {
+				t.Errorf("compile(%s) succeeded, want error", w)
+			}
+		})
+	}
+}
+
+func TestScheduledFlag(t *testing.T) {
+	const doc = `{"defaultVariant":"off","variants":{"on":true,"off":false},` +
+		`"rules":[{"if":{"attribute":"staff","op":"eq","value":true},"variant":"on"}],` +
+		`"schedule":[{"variant":"on","start":"2026-04-01T09:00:00+09:00","end":"2026-05-01T00:00:00+09:00"}]}`
+
+	var now time.Time
+	provider := NewSimpleEnvProvider(
+		WithSource(MapSource{"FT_LAUNCH": doc}),
+		WithClock(func() time.Time { return now }),
+	)
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	for name, test := range map[string]struct {
+		now     time.Time
+		evalCtx openfeature.FlattenedContext
+		want    openfeature.BoolResolutionDetail
+	}{
+		"before launch": {
+			now: time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC),
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "off",
+				},
+			},
+		},
+		"launched": {
+			now: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       ReasonSchedule,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{MetadataScheduleIndex: 0},
+				},
+			},
+		},
+		"expired": {
+			now: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			want: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "off",
+				},
+			},
+		},
+		"rules take precedence": {
+			now:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			evalCtx: openfeature.FlattenedContext{"staff": true},
+			want: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{MetadataRuleIndex: 0},
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			now = test.now
+
+			result := provider.BooleanEvaluation(context.Background(), "launch", false, test.evalCtx)
+			if diff := cmp.Diff(test.want, result, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScheduleValidation(t *testing.T) {
+	for name, doc := range map[string]string{
+		"undefined variant": `{"defaultVariant":"off","variants":{"off":false},"schedule":[{"variant":"on","days":["mon"]}]}`,
+		"invalid window":    `{"defaultVariant":"off","variants":{"off":false},"schedule":[{"variant":"off","days":["someday"]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_FLAG": doc}))
+			if err := provider.Init(openfeature.EvaluationContext{}); err == nil {
+				t.Error("Init() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// FileSuffix marks a variable holding the path of a file with the flag value,
+	// e.g. FT_API_MODE_FILE=/run/secrets/api_mode for the flag api_mode. A variable
+	// whose value names no file, such as FT_UPLOAD_FILE=true, is a flag of its own.
+	// The file is checked for changes on every evaluation, so a rotated secret is
+	// served without a Reload.
+	FileSuffix = "_FILE"
+	// DefaultMaxFileSize is the largest flag file that is read, in bytes.
+	DefaultMaxFileSize = 64 << 10
+)
+
+// WithMaxFileSize limits the size of files read through <prefix><KEY>_FILE variables.
+func WithMaxFileSize(n int64) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.maxFileSize = n
+	}
+}
+
+// lookupFile resolves key through its _FILE variable when the key itself is not set.
+func (p *SimpleEnvProvider) lookupFile(key string) (*flagValue, bool) {
+	path, ok := p.source.Lookup(key + FileSuffix)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := p.fileVariable(key+FileSuffix, path); !ok {
+		return nil, false
+	}
+	return p.readFlagFile(key+FileSuffix, path), true
+}
+
+// fileVariable reports whether the prefixed key is a _FILE variable, returning the
+// key of the flag it holds the file of. Only a value naming an existing file makes
+// it one; the file may still turn out unreadable or too large.
+func (p *SimpleEnvProvider) fileVariable(key, value string) (string, bool) {
+	base, ok := strings.CutSuffix(key, FileSuffix)
+	if !ok || !strings.HasPrefix(base, p.prefix) || base == p.prefix {
+		return "", false
+	}
+	info, err := os.Stat(value)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	return base, true
+}
+
+func (p *SimpleEnvProvider) readFlagFile(fileKey, path string) *flagValue {
+	cached, err := p.files.value(path, p.maxFileSize)
+	if err != nil {
+		return &flagValue{
+			fileErr:  fmt.Errorf("flag file named by %s could not be read: %w", fileKey, err),
+			fileKey:  fileKey,
+			filePath: path,
+		}
+	}
+	fv := *cached
+	fv.fileKey, fv.filePath = fileKey, path
+	return &fv
+}
+
+// fileCache keeps the content of flag files, re-reading a file only when its
+// modification time or size changes.
+type fileCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedFile
+}
+
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	content string
+	parsed  *flagValue // content parsed, once value has been asked for it
+}
+
+func (c *fileCache) read(path string, limit int64) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > limit {
+		return "", fmt.Errorf("%s is larger than %d bytes", path, limit)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[path]; ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return e.content, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// The file may have grown since Stat; never read past the limit.
+	b, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(b)) > limit {
+		return "", fmt.Errorf("%s is larger than %d bytes", path, limit)
+	}
+
+	content := strings.TrimRight(string(b), "\r\n")
+	if c.entries == nil {
+		c.entries = map[string]cachedFile{}
+	}
+	c.entries[path] = cachedFile{modTime: info.ModTime(), size: info.Size(), content: content}
+	return content, nil
+}
+
+// value returns the content of the file at path parsed as a flag value, parsing it
+// again only when the content changed.
+func (c *fileCache) value(path string, limit int64) (*flagValue, error) {
+	content, err := c.read(path, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.entries[path]
+	if e.parsed == nil || e.parsed.raw != content {
+		e.parsed = parseFlagValue(content)
+		c.entries[path] = e
+	}
+	return e.parsed, nil
+}
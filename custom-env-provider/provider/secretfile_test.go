@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestFileIndirection(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "api_mode")
+	writeFile(t, secret, "strict\n")
+	large := filepath.Join(dir, "large")
+	writeFile(t, large, strings.Repeat("x", 100))
+
+	errorComparer := cmp.Comparer(func(a, b openfeature.ResolutionError) bool {
+		return a.Error() == b.Error()
+	})
+
+	for name, test := range map[string]struct {
+		source  Source
+		opts    []ProviderOption
+		flagKey string // api_mode when empty
+		want    openfeature.StringResolutionDetail
+	}{
+		"file variable": {
+			source: MapSource{"FT_API_MODE_FILE": secret},
+			want: openfeature.StringResolutionDetail{
+				Value: "strict",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"file variable without snapshot": {
+			source: SourceFunc(MapSource{"FT_API_MODE_FILE": secret}.Lookup),
+			want: openfeature.StringResolutionDetail{
+				Value: "strict",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"plain variable wins": {
+			source: MapSource{"FT_API_MODE": "lenient", "FT_API_MODE_FILE": secret},
+			want: openfeature.StringResolutionDetail{
+				Value: "lenient",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"missing file": {
+			source: MapSource{"FT_API_MODE_FILE": filepath.Join(dir, "missing")},
+			want: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_API_MODE is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"missing file is a flag of its own": {
+			source:  MapSource{"FT_API_MODE_FILE": filepath.Join(dir, "missing")},
+			flagKey: "api_mode_file",
+			want: openfeature.StringResolutionDetail{
+				Value: filepath.Join(dir, "missing"),
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"flag named like a file variable": {
+			source:  MapSource{"FT_UPLOAD_FILE": "true"},
+			flagKey: "upload_file",
+			want: openfeature.StringResolutionDetail{
+				Value: "true",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: ReasonEnv,
+				},
+			},
+		},
+		"flag named like a file variable without snapshot": {
+			source:  SourceFunc(MapSource{"FT_UPLOAD_FILE": "true"}.Lookup),
+			flagKey: "upload",
+			want: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_UPLOAD is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"file too large": {
+			source: MapSource{"FT_API_MODE_FILE": large},
+			opts:   []ProviderOption{WithMaxFileSize(10)},
+			want: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewGeneralResolutionError("flag file named by FT_API_MODE_FILE could not be read: " + large + " is larger than 10 bytes"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			flagKey := test.flagKey
+			if flagKey == "" {
+				flagKey = "api_mode"
+			}
+			provider := NewSimpleEnvProvider(append(test.opts, WithSource(test.source))...)
+			result := provider.StringEvaluation(context.Background(), flagKey, "default", nil)
+
+			if diff := cmp.Diff(test.want, result, errorComparer); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+			want, got := test.want.ResolutionDetail(), result.ResolutionDetail()
+			if got.ErrorCode != want.ErrorCode || got.ErrorMessage != want.ErrorMessage {
+				t.Errorf("error = %s: %q, want %s: %q", got.ErrorCode, got.ErrorMessage, want.ErrorCode, want.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestFileRotation(t *testing.T) {
+	t.Parallel()
+
+	secret := filepath.Join(t.TempDir(), "api_mode")
+	writeFile(t, secret, "a")
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_API_MODE_FILE": secret}))
+
+	if got := provider.StringEvaluation(context.Background(), "api_mode", "", nil).Value; got != "a" {
+		t.Fatalf("StringEvaluation() = %q, want a", got)
+	}
+	// The snapshot is not reloaded; the rotated file is picked up on lookup.
+	writeFile(t, secret, "bbb")
+	if got := provider.StringEvaluation(context.Background(), "api_mode", "", nil).Value; got != "bbb" {
+		t.Errorf("StringEvaluation() after rotation = %q, want bbb", got)
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "flag")
+	writeFile(t, path, "one")
+
+	var c fileCache
+	if got, err := c.read(path, 10); err != nil || got != "one" {
+		t.Fatalf("read() = %q, %v, want one", got, err)
+	}
+
+	// Serve from the cache while the file is unchanged on disk.
+	c.entries[path] = cachedFile{modTime: c.entries[path].modTime, size: c.entries[path].size, content: "cached"}
+	if got, err := c.read(path, 10); err != nil || got != "cached" {
+		t.Errorf("read() = %q, %v, want cached", got, err)
+	}
+
+	writeFile(t, path, "three")
+	if got, err := c.read(path, 10); err != nil || got != "three" {
+		t.Errorf("read() after change = %q, %v, want three", got, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.read(path, 10); err == nil {
+		t.Error("read() of removed file succeeded")
+	}
+}
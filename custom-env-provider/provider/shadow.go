@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// DefaultShadowConcurrency bounds the shadow evaluations a ShadowProvider runs at once.
+const DefaultShadowConcurrency = 64
+
+// Mismatch is a flag evaluation on which the primary and secondary provider of a
+// ShadowProvider disagreed. A secondary that fails to initialize is reported as a
+// Mismatch without a FlagKey, whose Secondary result is a PROVIDER_NOT_READY error
+// carrying the Init error.
+type Mismatch struct {
+	FlagKey   string
+	Primary   ChildResult
+	Secondary ChildResult
+}
+
+// MismatchReporter records mismatches found by a ShadowProvider. It is called from
+// background goroutines, possibly concurrently.
+type MismatchReporter interface {
+	ReportMismatch(Mismatch)
+}
+
+// MismatchReporterFunc adapts a function to a MismatchReporter.
+type MismatchReporterFunc func(Mismatch)
+
+func (f MismatchReporterFunc) ReportMismatch(m Mismatch) {
+	f(m)
+}
+
+// ShadowProvider serves every evaluation from a primary provider and repeats it
+// against a secondary provider in the background, reporting the evaluations on
+// which they disagree. It is meant for validating a migration between providers,
+// e.g. from the from-env provider to SimpleEnvProvider, before switching over.
+//
+// Results disagree when their values differ or when only one of them is an error,
+// or both are errors with different codes. Reasons are not compared, as providers
+// name them differently.
+type ShadowProvider struct {
+	primary   openfeature.FeatureProvider
+	secondary openfeature.FeatureProvider
+	names     []string
+	reporter  MismatchReporter
+
+	sem chan struct{} // limits concurrent shadow evaluations
+	wg  sync.WaitGroup
+
+	mu     sync.Mutex // guards closed and orders wg.Add before Shutdown's wg.Wait
+	closed bool       // set by Shutdown; no shadow evaluation starts until Init
+
+	events chan openfeature.Event // never carries events; served when the primary has none
+}
+
+type ShadowProviderOption func(*ShadowProvider)
+
+func NewShadowProvider(primary, secondary openfeature.FeatureProvider, reporter MismatchReporter, opts ...ShadowProviderOption) *ShadowProvider {
+	s := &ShadowProvider{
+		primary:   primary,
+		secondary: secondary,
+		names:     childNames([]openfeature.FeatureProvider{primary, secondary}),
+		reporter:  reporter,
+		sem:       make(chan struct{}, DefaultShadowConcurrency),
+		events:    make(chan openfeature.Event),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithShadowConcurrency sets how many shadow evaluations may run at once. While
+// that many are in flight, further evaluations are served without a shadow.
+func WithShadowConcurrency(n int) ShadowProviderOption {
+	return func(s *ShadowProvider) {
+		s.sem = make(chan struct{}, n)
+	}
+}
+
+// Metadata reports the primary provider's name, as that is where results come from.
+func (s *ShadowProvider) Metadata() openfeature.Metadata {
+	return s.primary.Metadata()
+}
+
+func (s *ShadowProvider) Hooks() []openfeature.Hook {
+	return s.primary.Hooks()
+}
+
+var (
+	_ openfeature.StateHandler = (*ShadowProvider)(nil)
+	_ openfeature.EventHandler = (*ShadowProvider)(nil)
+)
+
+// Init initializes the primary provider, then the secondary. Only a failure of the
+// primary is returned: a secondary that cannot initialize is reported to the
+// reporter instead of taking the primary down with it.
+func (s *ShadowProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	s.mu.Lock()
+	s.closed = false
+	s.mu.Unlock()
+
+	var err error
+	if h, ok := s.primary.(openfeature.StateHandler); ok {
+		err = h.Init(evalCtx)
+	}
+	if h, ok := s.secondary.(openfeature.StateHandler); ok {
+		if secondaryErr := h.Init(evalCtx); secondaryErr != nil && s.reporter != nil {
+			s.reporter.ReportMismatch(Mismatch{
+				Primary: ChildResult{Provider: s.names[0]},
+				Secondary: ChildResult{Provider: s.names[1], Detail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewProviderNotReadyResolutionError(secondaryErr.Error()),
+					Reason:          openfeature.ErrorReason,
+				}},
+			})
+		}
+	}
+	return err
+}
+
+// Shutdown stops starting shadow evaluations, waits for those in flight and shuts
+// both providers down.
+func (s *ShadowProvider) Shutdown() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	for _, p := range []openfeature.FeatureProvider{s.primary, s.secondary} {
+		if h, ok := p.(openfeature.StateHandler); ok {
+			h.Shutdown()
+		}
+	}
+}
+
+// EventChannel implements openfeature.EventHandler with the events of the primary,
+// or a channel that carries none when the primary emits no events.
+func (s *ShadowProvider) EventChannel() <-chan openfeature.Event {
+	if h, ok := s.primary.(openfeature.EventHandler); ok {
+		return h.EventChannel()
+	}
+	return s.events
+}
+
+func (s *ShadowProvider) BooleanEvaluation(ctx context.Context, flagKey string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	val, detail := shadowEvaluate(s, ctx, flagKey, func(ctx context.Context, p openfeature.FeatureProvider) (bool, openfeature.ProviderResolutionDetail) {
+		res := p.BooleanEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.BoolResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowProvider) StringEvaluation(ctx context.Context, flagKey string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	val, detail := shadowEvaluate(s, ctx, flagKey, func(ctx context.Context, p openfeature.FeatureProvider) (string, openfeature.ProviderResolutionDetail) {
+		res := p.StringEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.StringResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowProvider) IntEvaluation(ctx context.Context, flagKey string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	val, detail := shadowEvaluate(s, ctx, flagKey, func(ctx context.Context, p openfeature.FeatureProvider) (int64, openfeature.ProviderResolutionDetail) {
+		res := p.IntEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.IntResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowProvider) FloatEvaluation(ctx context.Context, flagKey string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	val, detail := shadowEvaluate(s, ctx, flagKey, func(ctx context.Context, p openfeature.FeatureProvider) (float64, openfeature.ProviderResolutionDetail) {
+		res := p.FloatEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.FloatResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+func (s *ShadowProvider) ObjectEvaluation(ctx context.Context, flagKey string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	val, detail := shadowEvaluate(s, ctx, flagKey, func(ctx context.Context, p openfeature.FeatureProvider) (interface{}, openfeature.ProviderResolutionDetail) {
+		res := p.ObjectEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		return res.Value, res.ProviderResolutionDetail
+	})
+	return openfeature.InterfaceResolutionDetail{Value: val, ProviderResolutionDetail: detail}
+}
+
+// shadowEvaluate returns the primary's result and, capacity permitting, starts
+// evaluating the secondary in the background.
+func shadowEvaluate[T any](s *ShadowProvider, ctx context.Context, flagKey string, eval func(context.Context, openfeature.FeatureProvider) (T, openfeature.ProviderResolutionDetail)) (T, openfeature.ProviderResolutionDetail) {
+	val, detail := eval(ctx, s.primary)
+	if s.reporter == nil {
+		return val, detail
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+	default:
+		return val, detail
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		<-s.sem
+		return val, detail
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.sem }()
+
+		// The shadow outlives the caller's evaluation, so it must not be cut short
+		// when the caller's context is.
+		shadowVal, shadowDetail := eval(context.WithoutCancel(ctx), s.secondary)
+		if resultsAgree(val, detail, shadowVal, shadowDetail) {
+			return
+		}
+		s.reporter.ReportMismatch(Mismatch{
+			FlagKey:   flagKey,
+			Primary:   ChildResult{Provider: s.names[0], Value: val, Detail: detail},
+			Secondary: ChildResult{Provider: s.names[1], Value: shadowVal, Detail: shadowDetail},
+		})
+	}()
+
+	return val, detail
+}
+
+func resultsAgree(a interface{}, aDetail openfeature.ProviderResolutionDetail, b interface{}, bDetail openfeature.ProviderResolutionDetail) bool {
+	aCode := aDetail.ResolutionDetail().ErrorCode
+	bCode := bDetail.ResolutionDetail().ErrorCode
+	if aCode != "" || bCode != "" {
+		return aCode == bCode
+	}
+	return reflect.DeepEqual(a, b)
+}
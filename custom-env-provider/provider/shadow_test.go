@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+)
+
+func TestShadowProvider(t *testing.T) {
+	primary := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_SAME":     "10",
+		"FT_CHANGED":  "20",
+		"FT_ONLY_OLD": "30",
+		"FT_BROKEN":   "x",
+	}))
+	secondary := NewSimpleEnvProvider(WithSource(MapSource{
+		"FT_SAME":    "10",
+		"FT_CHANGED": "21",
+		"FT_BROKEN":  "y",
+	}))
+
+	var (
+		mu         sync.Mutex
+		mismatches []Mismatch
+	)
+	s := NewShadowProvider(primary, secondary, MismatchReporterFunc(func(m Mismatch) {
+		mu.Lock()
+		defer mu.Unlock()
+		mismatches = append(mismatches, m)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for flagKey, want := range map[string]int64{
+		"same":     10,
+		"changed":  20,
+		"only_old": 30,
+		"broken":   -1,
+	} {
+		if got := s.IntEvaluation(ctx, flagKey, -1, nil); got.Value != want {
+			t.Errorf("IntEvaluation(%s) = %d, want the primary's %d", flagKey, got.Value, want)
+		}
+	}
+	// Shadow evaluations must survive the caller's context.
+	cancel()
+	s.Shutdown()
+
+	want := map[string]Mismatch{
+		"changed": {
+			FlagKey:   "changed",
+			Primary:   ChildResult{Provider: "simple-env-flag-evaluator#0", Value: int64(20), Detail: openfeature.ProviderResolutionDetail{Reason: ReasonEnv}},
+			Secondary: ChildResult{Provider: "simple-env-flag-evaluator#1", Value: int64(21), Detail: openfeature.ProviderResolutionDetail{Reason: ReasonEnv}},
+		},
+		"only_old": {
+			FlagKey: "only_old",
+			Primary: ChildResult{Provider: "simple-env-flag-evaluator#0", Value: int64(30), Detail: openfeature.ProviderResolutionDetail{Reason: ReasonEnv}},
+			Secondary: ChildResult{Provider: "simple-env-flag-evaluator#1", Value: int64(-1), Detail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag FT_ONLY_OLD is not set"),
+				Reason:          openfeature.ErrorReason,
+			}},
+		},
+	}
+	got := make(map[string]Mismatch, len(mismatches))
+	for _, m := range mismatches {
+		got[m.FlagKey] = m
+	}
+	opts := cmp.Comparer(func(a, b openfeature.ResolutionError) bool { return a.Error() == b.Error() })
+	if diff := cmp.Diff(want, got, opts); diff != "" {
+		t.Errorf("mismatches (-want +got):\n%s", diff)
+	}
+}
+
+func TestShadowProviderConcurrencyLimit(t *testing.T) {
+	block := make(chan struct{})
+	secondary := NewSimpleEnvProvider(WithLookupFunc(func(key string) (string, bool) {
+		<-block
+		return "2", true
+	}))
+	primary := NewSimpleEnvProvider(WithSource(MapSource{"FT_LIMIT": "1"}))
+
+	var (
+		mu    sync.Mutex
+		count int
+	)
+	s := NewShadowProvider(primary, secondary, MismatchReporterFunc(func(Mismatch) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}), WithShadowConcurrency(2))
+
+	for i := 0; i < 5; i++ {
+		if got := s.IntEvaluation(context.Background(), "limit", 0, nil); got.Value != 1 {
+			t.Fatalf("IntEvaluation() = %d, want 1", got.Value)
+		}
+	}
+	close(block)
+	s.Shutdown()
+
+	if count != 2 {
+		t.Errorf("got %d shadow mismatches, want 2 (the concurrency limit)", count)
+	}
+}
+
+func TestShadowProviderWithoutReporter(t *testing.T) {
+	primary := NewSimpleEnvProvider(WithSource(MapSource{"FT_FLAG": "on"}))
+	s := NewShadowProvider(primary, NewSimpleEnvProvider(WithSource(MapSource{})), nil)
+
+	got := s.StringEvaluation(context.Background(), "flag", "", nil)
+	want := openfeature.StringResolutionDetail{
+		Value:                    "on",
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: ReasonEnv},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestShadowProviderShutdownDuringEvaluations(t *testing.T) {
+	primary := NewSimpleEnvProvider(WithSource(MapSource{"FT_FLAG": "1"}))
+	secondary := NewSimpleEnvProvider(WithSource(MapSource{"FT_FLAG": "2"}))
+
+	var shutdown, late atomic.Bool
+	s := NewShadowProvider(primary, secondary, MismatchReporterFunc(func(Mismatch) {
+		if shutdown.Load() {
+			late.Store(true)
+		}
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				s.IntEvaluation(context.Background(), "flag", 0, nil)
+			}
+		}()
+	}
+	s.Shutdown()
+	shutdown.Store(true)
+	wg.Wait()
+
+	if late.Load() {
+		t.Error("shadow evaluation ran after Shutdown returned")
+	}
+}
+
+// initRecorder is a provider whose Init records its name and returns err.
+type initRecorder struct {
+	openfeature.FeatureProvider
+	name  string
+	inits *[]string
+	err   error
+}
+
+func (r initRecorder) Init(openfeature.EvaluationContext) error {
+	*r.inits = append(*r.inits, r.name)
+	return r.err
+}
+
+func (initRecorder) Shutdown() {}
+
+func TestShadowProviderInit(t *testing.T) {
+	var inits []string
+	primary := initRecorder{FeatureProvider: NewSimpleEnvProvider(), name: "primary", inits: &inits}
+	secondary := initRecorder{FeatureProvider: NewSimpleEnvProvider(), name: "secondary", inits: &inits, err: errors.New("source unavailable")}
+
+	var mismatches []Mismatch
+	s := NewShadowProvider(primary, secondary, MismatchReporterFunc(func(m Mismatch) {
+		mismatches = append(mismatches, m)
+	}))
+	if err := s.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Init() error = %v, want the secondary's failure not to be returned", err)
+	}
+	s.Shutdown()
+
+	if diff := cmp.Diff([]string{"primary", "secondary"}, inits); diff != "" {
+		t.Errorf("Init order mismatch (-want +got):\n%s", diff)
+	}
+	want := []Mismatch{{
+		Primary: ChildResult{Provider: "simple-env-flag-evaluator#0"},
+		Secondary: ChildResult{Provider: "simple-env-flag-evaluator#1", Detail: openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewProviderNotReadyResolutionError("source unavailable"),
+			Reason:          openfeature.ErrorReason,
+		}},
+	}}
+	opts := cmp.Comparer(func(a, b openfeature.ResolutionError) bool { return a.Error() == b.Error() })
+	if diff := cmp.Diff(want, mismatches, opts); diff != "" {
+		t.Errorf("mismatches (-want +got):\n%s", diff)
+	}
+}
+
+func TestShadowProviderEventChannel(t *testing.T) {
+	primary := memprovider.NewInMemoryProvider(nil)
+	s := NewShadowProvider(primary, NewSimpleEnvProvider(), nil)
+
+	events := s.EventChannel()
+	if events == nil {
+		t.Fatal("EventChannel() = nil for a primary without events")
+	}
+	select {
+	case e := <-events:
+		t.Errorf("EventChannel() carried %v, want no events", e)
+	default:
+	}
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// flagValue is a raw flag value parsed once into every type it can be served as.
+type flagValue struct {
+	raw      string
+	doc      evaluator
+	fileErr  error // set when the value had to be read from a _FILE variable and could not be
+	override bool  // set for values from SetOverride
+
+	// fileKey and filePath name the _FILE variable and the file the value was
+	// read from, so that lookups can pick up a rotated file.
+	fileKey  string
+	filePath string
+
+	boolVal  bool
+	boolErr  error
+	intVal   int64
+	intErr   error
+	floatVal float64
+	floatErr error
+	objVal   interface{}
+	objErr   error
+}
+
+func parseFlagValue(raw string) *flagValue {
+	fv := &flagValue{raw: raw}
+	if doc, ok := parseFlagDocument(raw); ok {
+		fv.doc = doc
+		return fv
+	}
+
+	fv.boolVal, fv.boolErr = strconv.ParseBool(raw)
+	fv.intVal, fv.intErr = strconv.ParseInt(raw, 10, 64)
+	fv.floatVal, fv.floatErr = strconv.ParseFloat(raw, 64)
+	fv.objErr = json.Unmarshal([]byte(raw), &fv.objVal)
+	return fv
+}
+
+// snapshot is an immutable table of parsed flag values keyed by their prefixed key.
+// It is replaced as a whole on Reload and never modified afterwards, so evaluations
+// can read it without locking.
+type snapshot struct {
+	flags map[string]*flagValue
+}
+
+// Reload re-reads every prefixed key from the source and atomically replaces the
+// flag snapshot that evaluations are served from. Sources that do not implement
+// Lister are not snapshotted and are read on every evaluation instead.
+//
+// Once the provider is initialized, a reload that adds, removes or changes flags emits a
+// PROVIDER_CONFIGURATION_CHANGED event listing the affected flag keys.
+func (p *SimpleEnvProvider) Reload() error {
+	lister, ok := p.source.(Lister)
+	if !ok {
+		return nil
+	}
+
+	keys, err := lister.Keys()
+	if err != nil {
+		return fmt.Errorf("list flags: %w", err)
+	}
+
+	snap := &snapshot{
+		flags: make(map[string]*flagValue),
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, p.prefix) {
+			continue
+		}
+		raw, ok := p.source.Lookup(key)
+		if !ok {
+			continue
+		}
+		// A _FILE variable naming a file holds the value of another flag rather
+		// than being a flag of its own.
+		if base, ok := p.fileVariable(key, raw); ok {
+			if _, set := p.source.Lookup(base); !set {
+				snap.flags[base] = p.readFlagFile(key, raw)
+			}
+			continue
+		}
+		snap.flags[key] = parseFlagValue(raw)
+	}
+
+	old := p.snapshot.Swap(snap)
+	if old != nil && p.Status() != openfeature.NotReadyState {
+		if changed := p.changedFlags(old, snap); len(changed) > 0 {
+			p.emit(openfeature.Event{
+				ProviderName: p.Metadata().Name,
+				EventType:    openfeature.ProviderConfigChange,
+				ProviderEventDetails: openfeature.ProviderEventDetails{
+					Message:     "flags reloaded",
+					FlagChanges: changed,
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// changedFlags returns the sorted keys of flags that differ between two snapshots,
+// in the form callers evaluate them, e.g. "my_feature" for FT_MY_FEATURE.
+func (p *SimpleEnvProvider) changedFlags(old, cur *snapshot) []string {
+	var changed []string
+	for key, fv := range cur.flags {
+		if prev, ok := old.flags[key]; !ok || prev.raw != fv.raw {
+			changed = append(changed, p.flagKey(key))
+		}
+	}
+	for key := range old.flags {
+		if _, ok := cur.flags[key]; !ok {
+			changed = append(changed, p.flagKey(key))
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// FlagKeys returns the sorted keys of the flags in the current snapshot and of the
+// overridden flags, in the form callers evaluate them. Sources that cannot be
+// listed contribute no keys.
+func (p *SimpleEnvProvider) FlagKeys() []string {
+	seen := make(map[string]struct{})
+	if snap := p.snapshot.Load(); snap != nil {
+		for key := range snap.flags {
+			seen[p.flagKey(key)] = struct{}{}
+		}
+	}
+	for _, o := range p.Overrides() {
+		seen[o.FlagKey] = struct{}{}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flagKey converts a prefixed source key back into a flag key.
+func (p *SimpleEnvProvider) flagKey(key string) string {
+	return strings.ToLower(strings.TrimPrefix(key, p.prefix))
+}
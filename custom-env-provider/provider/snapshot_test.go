@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	src := MapSource{"FT_COUNT": "1", "OTHER_COUNT": "2"}
+	provider := NewSimpleEnvProvider(WithSource(src))
+
+	if got := len(provider.snapshot.Load().flags); got != 1 {
+		t.Errorf("snapshot holds %d flags, want 1", got)
+	}
+
+	src["FT_COUNT"] = "10"
+	if got := provider.IntEvaluation(context.Background(), "count", 0, nil).Value; got != 1 {
+		t.Errorf("before reload got %d, want 1", got)
+	}
+
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := provider.IntEvaluation(context.Background(), "count", 0, nil).Value; got != 10 {
+		t.Errorf("after reload got %d, want 10", got)
+	}
+
+	delete(src, "FT_COUNT")
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if res := provider.IntEvaluation(context.Background(), "count", 0, nil); res.Reason != openfeature.ErrorReason {
+		t.Errorf("after removal got %+v, want flag not found", res)
+	}
+}
+
+func TestFlagKeys(t *testing.T) {
+	t.Parallel()
+
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_B_FLAG": "1", "FT_A_FLAG": "2", "OTHER": "3"}))
+	if diff := cmp.Diff([]string{"a_flag", "b_flag"}, provider.FlagKeys()); diff != "" {
+		t.Errorf("FlagKeys() mismatch (-want +got):\n%s", diff)
+	}
+
+	secret := filepath.Join(t.TempDir(), "api_mode")
+	writeFile(t, secret, "strict")
+	withFile := NewSimpleEnvProvider(WithSource(MapSource{"FT_API_MODE_FILE": secret}))
+	if diff := cmp.Diff([]string{"api_mode"}, withFile.FlagKeys()); diff != "" {
+		t.Errorf("FlagKeys() with a _FILE variable mismatch (-want +got):\n%s", diff)
+	}
+
+	unlisted := NewSimpleEnvProvider(WithLookupFunc(func(string) (string, bool) { return "", false }))
+	if got := unlisted.FlagKeys(); len(got) != 0 {
+		t.Errorf("FlagKeys() for an unlisted source = %v, want none", got)
+	}
+}
+
+func TestReloadConcurrentEvaluation(t *testing.T) {
+	t.Parallel()
+
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_MY_FEATURE": "true"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if !provider.BooleanEvaluation(context.Background(), "my_feature", false, nil).Value {
+					t.Error("got false, want true")
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := provider.Reload(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseFlagValue(t *testing.T) {
+	fv := parseFlagValue("42")
+	if fv.boolErr == nil || fv.intErr != nil || fv.floatErr != nil || fv.objErr != nil {
+		t.Errorf("parseFlagValue(42) errors = bool %v, int %v, float %v, obj %v", fv.boolErr, fv.intErr, fv.floatErr, fv.objErr)
+	}
+	if fv.intVal != 42 || fv.floatVal != 42 {
+		t.Errorf("parseFlagValue(42) = int %d, float %g", fv.intVal, fv.floatVal)
+	}
+
+	fv = parseFlagValue(`{"defaultVariant":"on","variants":[{"name":"on","value":true}]}`)
+	if fv.doc == nil {
+		t.Error("parseFlagValue did not detect variant document")
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"os"
+	"strings"
+)
+
+// Source looks up raw flag values by their prefixed key, e.g. "FT_MY_FEATURE".
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// Lister is implemented by sources that can enumerate their keys. The provider
+// pre-loads every prefixed key of such a source into a snapshot; see Reload.
+type Lister interface {
+	Keys() ([]string, error)
+}
+
+// SourceFunc adapts an ordinary lookup function such as os.LookupEnv to a Source.
+type SourceFunc func(key string) (string, bool)
+
+func (f SourceFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
+
+// EnvSource reads flag values from the process environment. It is the default source.
+type EnvSource struct{}
+
+func (EnvSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (EnvSource) Keys() ([]string, error) {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		if k, _, ok := strings.Cut(kv, "="); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// MapSource serves flag values from a map, which is mostly useful in tests.
+// Changes to the map are picked up on the provider's next Reload.
+type MapSource map[string]string
+
+func (m MapSource) Lookup(key string) (string, bool) {
+	val, ok := m[key]
+	return val, ok
+}
+
+func (m MapSource) Keys() ([]string, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestSourceLookup(t *testing.T) {
+	t.Setenv("FT_SOURCE_TEST", "from-env")
+
+	for name, test := range map[string]struct {
+		source  Source
+		key     string
+		wantVal string
+		wantOK  bool
+	}{
+		"env source set": {
+			source:  EnvSource{},
+			key:     "FT_SOURCE_TEST",
+			wantVal: "from-env",
+			wantOK:  true,
+		},
+		"env source unset": {
+			source: EnvSource{},
+			key:    "FT_SOURCE_TEST_UNSET",
+		},
+		"map source set": {
+			source:  MapSource{"FT_KEY": "from-map"},
+			key:     "FT_KEY",
+			wantVal: "from-map",
+			wantOK:  true,
+		},
+		"map source unset": {
+			source: MapSource{},
+			key:    "FT_KEY",
+		},
+		"func source": {
+			source: SourceFunc(func(key string) (string, bool) {
+				return key + "-value", true
+			}),
+			key:     "FT_KEY",
+			wantVal: "FT_KEY-value",
+			wantOK:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			val, ok := test.source.Lookup(test.key)
+			if val != test.wantVal || ok != test.wantOK {
+				t.Errorf("Lookup(%q) = (%q, %v), want (%q, %v)", test.key, val, ok, test.wantVal, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestWithLookupFunc(t *testing.T) {
+	t.Parallel()
+
+	var looked []string
+	provider := NewSimpleEnvProvider(WithPrefix("APP_"), WithLookupFunc(func(key string) (string, bool) {
+		looked = append(looked, key)
+		return "true", true
+	}))
+
+	result := provider.BooleanEvaluation(context.Background(), "my_feature", false, nil)
+
+	want := openfeature.BoolResolutionDetail{
+		Value: true,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Reason: ReasonEnv,
+		},
+	}
+	opts := []cmp.Option{
+		cmpopts.IgnoreUnexported(openfeature.ResolutionError{}),
+	}
+	if diff := cmp.Diff(want, result, opts...); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"APP_MY_FEATURE"}, looked); diff != "" {
+		t.Errorf("looked up keys mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var _ openfeature.StateHandler = (*SimpleEnvProvider)(nil)
+
+// Init loads the flag snapshot and validates every flag in it. A source that cannot
+// be read is reported as a plain error, leaving the provider in the ERROR state;
+// malformed flag values are reported as a ProviderInitError with PROVIDER_FATAL,
+// as they will not go away until the configuration is fixed.
+func (p *SimpleEnvProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	if err := p.Reload(); err != nil {
+		p.state.Store(openfeature.ErrorState)
+		return err
+	}
+
+	if err := p.validate(); err != nil {
+		p.state.Store(openfeature.FatalState)
+		return &openfeature.ProviderInitError{
+			ErrorCode: openfeature.ProviderFatalCode,
+			Message:   err.Error(),
+		}
+	}
+
+	p.state.Store(openfeature.ReadyState)
+
+	p.mu.Lock()
+	if p.done == nil {
+		p.done = make(chan struct{})
+		p.watch(p.done)
+	}
+	p.mu.Unlock()
+
+	return nil
+}
+
+// Shutdown stops any background work started by Init and waits for it to finish.
+func (p *SimpleEnvProvider) Shutdown() {
+	p.mu.Lock()
+	if p.done != nil {
+		close(p.done)
+		p.done = nil
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	p.state.Store(openfeature.NotReadyState)
+}
+
+// Status is the state Init left the provider in: READY, ERROR for a source that
+// could not be read or FATAL for a malformed flag. Once the source is watched, a
+// change to it sets READY or ERROR by whether the reload that follows succeeded.
+// Before Init and after Shutdown it is NOT_READY.
+func (p *SimpleEnvProvider) Status() openfeature.State {
+	if s, ok := p.state.Load().(openfeature.State); ok {
+		return s
+	}
+	return openfeature.NotReadyState
+}
+
+// validate reports every malformed flag in the current snapshot.
+func (p *SimpleEnvProvider) validate() error {
+	snap := p.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(snap.flags))
+	for key := range snap.flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := snap.flags[key].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	errs = append(errs, p.validatePrerequisites(snap)...)
+	return errors.Join(errs...)
+}
+
+// validate reports values that can never be served: JSON that does not parse and
+// variant documents that do not resolve. Anything else is at least a valid string.
+func (fv *flagValue) validate() error {
+	if fv.fileErr != nil {
+		return fv.fileErr
+	}
+	if fv.doc != nil {
+		return fv.doc.validate()
+	}
+
+	trimmed := strings.TrimSpace(fv.raw)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && fv.objErr != nil {
+		return fmt.Errorf("invalid JSON: %w", fv.objErr)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestInit(t *testing.T) {
+	for name, test := range map[string]struct {
+		source    Source
+		wantErr   bool
+		wantCode  openfeature.ErrorCode
+		wantState openfeature.State
+	}{
+		"valid flags": {
+			source:    MapSource{"FT_MY_FEATURE": "true", "FT_LIMITS": `{"rps":10}`},
+			wantState: openfeature.ReadyState,
+		},
+		"malformed json": {
+			source:    MapSource{"FT_LIMITS": `{"rps":`},
+			wantErr:   true,
+			wantCode:  openfeature.ProviderFatalCode,
+			wantState: openfeature.FatalState,
+		},
+		"undefined default variant": {
+			source:    MapSource{"FT_COLOR": `{"defaultVariant":"missing","variants":[{"name":"on","value":true}]}`},
+			wantErr:   true,
+			wantCode:  openfeature.ProviderFatalCode,
+			wantState: openfeature.FatalState,
+		},
+		"unlistable source": {
+			source:    failingSource{},
+			wantErr:   true,
+			wantState: openfeature.ErrorState,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			provider := NewSimpleEnvProvider(WithSource(test.source))
+			domain := "init-" + name
+
+			err := openfeature.SetNamedProviderAndWait(domain, provider)
+			t.Cleanup(func() {
+				_ = openfeature.SetNamedProvider(domain, openfeature.NoopProvider{})
+			})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("SetNamedProviderAndWait() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantCode != "" {
+				var initErr *openfeature.ProviderInitError
+				if !errors.As(err, &initErr) || initErr.ErrorCode != test.wantCode {
+					t.Errorf("SetNamedProviderAndWait() error = %v, want code %s", err, test.wantCode)
+				}
+			}
+
+			if got := openfeature.NewClient(domain).State(); got != test.wantState {
+				t.Errorf("client state = %s, want %s", got, test.wantState)
+			}
+			if got := provider.Status(); got != test.wantState {
+				t.Errorf("provider status = %s, want %s", got, test.wantState)
+			}
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	provider := NewSimpleEnvProvider(WithSource(MapSource{"FT_MY_FEATURE": "true"}))
+	if err := openfeature.SetNamedProviderAndWait("shutdown", provider); err != nil {
+		t.Fatalf("SetNamedProviderAndWait() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = openfeature.SetNamedProvider("shutdown", openfeature.NoopProvider{})
+	})
+
+	client := openfeature.NewClient("shutdown")
+	if !client.Boolean(context.Background(), "my_feature", false, openfeature.EvaluationContext{}) {
+		t.Error("got false, want true")
+	}
+
+	provider.Shutdown()
+	if got := provider.Status(); got != openfeature.NotReadyState {
+		t.Errorf("provider status after Shutdown = %s, want %s", got, openfeature.NotReadyState)
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) Lookup(string) (string, bool) { return "", false }
+
+func (failingSource) Keys() ([]string, error) { return nil, errors.New("source unavailable") }
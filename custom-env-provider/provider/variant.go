@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// variantFlag is the flag document understood by the from-env provider, e.g.
+//
+//	{"defaultVariant":"off","variants":[{"name":"on","targetingKey":"","criteria":[{"key":"color","value":"yellow"}],"value":true}]}
+type variantFlag struct {
+	DefaultVariant string    `json:"defaultVariant"`
+	Variants       []variant `json:"variants"`
+}
+
+type variant struct {
+	Name         string      `json:"name"`
+	TargetingKey string      `json:"targetingKey"`
+	Criteria     []criterion `json:"criteria"`
+	Value        interface{} `json:"value"`
+}
+
+type criterion struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// evaluate returns the first variant whose targeting key and criteria match
+// evalCtx, falling back to the default variant.
+func (f *variantFlag) evaluate(flagKey string, evalCtx openfeature.FlattenedContext, _ scope) (evaluation, error) {
+	for _, v := range f.Variants {
+		if v.TargetingKey != "" && v.TargetingKey != evalCtx[openfeature.TargetingKey] {
+			continue
+		}
+		if v.matches(evalCtx) {
+			return evaluation{variant: v.Name, value: v.Value, reason: openfeature.TargetingMatchReason}, nil
+		}
+	}
+
+	for _, v := range f.Variants {
+		if v.Name == f.DefaultVariant {
+			return evaluation{variant: v.Name, value: v.Value, reason: openfeature.DefaultReason}, nil
+		}
+	}
+
+	return evaluation{}, fmt.Errorf("default variant %q is not defined", f.DefaultVariant)
+}
+
+// validate checks that the document names its variants uniquely and defines its default variant.
+func (f *variantFlag) validate() error {
+	names := make(map[string]struct{}, len(f.Variants))
+	for i, v := range f.Variants {
+		if v.Name == "" {
+			return fmt.Errorf("variant %d has no name", i)
+		}
+		if _, ok := names[v.Name]; ok {
+			return fmt.Errorf("variant %q is defined more than once", v.Name)
+		}
+		names[v.Name] = struct{}{}
+	}
+	if _, ok := names[f.DefaultVariant]; !ok {
+		return fmt.Errorf("default variant %q is not defined", f.DefaultVariant)
+	}
+	return nil
+}
+
+func (v variant) matches(evalCtx openfeature.FlattenedContext) bool {
+	for _, c := range v.Criteria {
+		val, ok := evalCtx[c.Key]
+		if !ok || !valuesEqual(val, c.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares a context attribute with a JSON decoded value, treating
+// all numeric types as float64.
+func valuesEqual(a, b interface{}) bool {
+	if fa, ok := toFloat64(a); ok {
+		fb, ok := toFloat64(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// toInt64 converts a JSON decoded number to int64, rejecting fractional values.
+func toInt64(v interface{}) (int64, bool) {
+	f, ok := toFloat64(v)
+	if !ok || f != float64(int64(f)) {
+		return 0, false
+	}
+	return int64(f), true
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestVariantFlagEvaluate(t *testing.T) {
+	flag, ok := parseFlagDocument(`{"defaultVariant":"other","variants":[{"name":"answer","criteria":[{"key":"n","value":42},{"key":"tier","value":"gold"}],"value":"yes"},{"name":"other","criteria":[{"key":"never","value":true}],"value":"no"}]}`)
+	if !ok {
+		t.Fatal("failed to parse variant flag")
+	}
+
+	type result struct {
+		Variant string
+		Value   interface{}
+		Reason  openfeature.Reason
+	}
+	for name, test := range map[string]struct {
+		evalCtx openfeature.FlattenedContext
+		want    result
+	}{
+		"all criteria match with int attribute": {
+			evalCtx: openfeature.FlattenedContext{"n": 42, "tier": "gold"},
+			want:    result{Variant: "answer", Value: "yes", Reason: openfeature.TargetingMatchReason},
+		},
+		"partial match": {
+			evalCtx: openfeature.FlattenedContext{"n": 42},
+			want:    result{Variant: "other", Value: "no", Reason: openfeature.DefaultReason},
+		},
+		"nil context": {
+			want: result{Variant: "other", Value: "no", Reason: openfeature.DefaultReason},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := flag.evaluate("test_flag", test.evalCtx, scope{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, result{Variant: res.variant, Value: res.value, Reason: res.reason}); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
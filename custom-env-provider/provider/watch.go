@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// DefaultPollInterval is how often watched sources are checked for changes.
+const DefaultPollInterval = 5 * time.Second
+
+// watchable is implemented by sources whose content can change underneath the
+// provider, such as files on disk.
+type watchable interface {
+	// version returns a value that changes whenever the content does.
+	version() (string, error)
+}
+
+// WithPollInterval sets how often a watched source such as an env file is checked
+// for changes. A zero or negative interval disables watching.
+func WithPollInterval(d time.Duration) ProviderOption {
+	return func(p *SimpleEnvProvider) {
+		p.pollInterval = d
+	}
+}
+
+// watch polls the source until done is closed and reloads the flags whenever it changes.
+func (p *SimpleEnvProvider) watch(done <-chan struct{}) {
+	w, ok := p.source.(watchable)
+	if !ok || p.pollInterval <= 0 {
+		return
+	}
+
+	last, _ := w.version()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			v, err := w.version()
+			if err != nil || v == last {
+				continue
+			}
+			last = v
+			p.refresh()
+		}
+	}()
+}
+
+// refresh reloads and validates the flags after a source change, moving the
+// provider between READY and ERROR accordingly. Recovering from ERROR emits
+// PROVIDER_READY, as a source reverted to its last good content changes no flags
+// and so emits no PROVIDER_CONFIGURATION_CHANGED that would clear the error.
+func (p *SimpleEnvProvider) refresh() {
+	err := p.Reload()
+	if err == nil {
+		err = p.validate()
+	}
+	if err != nil {
+		p.state.Store(openfeature.ErrorState)
+		p.emit(openfeature.Event{
+			ProviderName: p.Metadata().Name,
+			EventType:    openfeature.ProviderError,
+			ProviderEventDetails: openfeature.ProviderEventDetails{
+				Message:   err.Error(),
+				ErrorCode: openfeature.GeneralCode,
+			},
+		})
+		return
+	}
+	if prev := p.state.Swap(openfeature.ReadyState); prev == openfeature.ErrorState {
+		p.emit(openfeature.Event{
+			ProviderName: p.Metadata().Name,
+			EventType:    openfeature.ProviderReady,
+			ProviderEventDetails: openfeature.ProviderEventDetails{
+				Message: "flags recovered",
+			},
+		})
+	}
+}
@@ -0,0 +1,337 @@
+// Package providertest provides an in-memory OpenFeature provider for testing code
+// that evaluates flags, without touching the process environment.
+//
+// Each test creates its own Provider and client, so tests can run in parallel:
+//
+//	func TestCheckout(t *testing.T) {
+//		t.Parallel()
+//
+//		flags := providertest.New()
+//		flags.Set("new_checkout", true)
+//		client := providertest.NewClient(t, flags)
+//
+//		runCheckout(client)
+//
+//		flags.AssertEvaluated(t, "new_checkout")
+//	}
+package providertest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Evaluation is a flag evaluation the provider served.
+type Evaluation struct {
+	FlagKey string
+	Type    openfeature.Type
+	Context openfeature.FlattenedContext
+	Value   interface{}
+	Reason  openfeature.Reason
+}
+
+// Provider serves flag values set by the test. It is safe for concurrent use.
+type Provider struct {
+	mu          sync.Mutex
+	flags       map[string]interface{}
+	overrides   []*contextOverride
+	evaluations []Evaluation
+}
+
+// contextOverride serves value for flagKey to evaluation contexts carrying every
+// attribute in match.
+type contextOverride struct {
+	flagKey string
+	match   openfeature.FlattenedContext
+	value   interface{}
+}
+
+func New() *Provider {
+	return &Provider{
+		flags: make(map[string]interface{}),
+	}
+}
+
+// NewClient registers p for a domain named after the test and returns a client
+// bound to it. The registration is replaced by a no-op provider when the test ends.
+func NewClient(t testing.TB, p *Provider) *openfeature.Client {
+	t.Helper()
+
+	domain := t.Name()
+	if err := openfeature.SetNamedProviderAndWait(domain, p); err != nil {
+		t.Fatalf("providertest: set provider: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = openfeature.SetNamedProvider(domain, openfeature.NoopProvider{})
+	})
+	return openfeature.NewClient(domain)
+}
+
+// Set serves value for flagKey for the lifetime of the provider.
+func (p *Provider) Set(flagKey string, value interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flags[flagKey] = value
+}
+
+// Override serves value for flagKey until the test ends, then restores the
+// previous value, or removes the flag if it was not set.
+func (p *Provider) Override(t testing.TB, flagKey string, value interface{}) {
+	t.Helper()
+
+	p.mu.Lock()
+	prev, had := p.flags[flagKey]
+	p.flags[flagKey] = value
+	p.mu.Unlock()
+
+	t.Cleanup(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if had {
+			p.flags[flagKey] = prev
+		} else {
+			delete(p.flags, flagKey)
+		}
+	})
+}
+
+// OverrideForContext serves value for flagKey, until the test ends, to evaluation
+// contexts that carry every attribute in match, e.g. a single user:
+//
+//	flags.OverrideForContext(t, "beta", openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}, true)
+//
+// Context overrides take precedence over Set and Override; the latest matching
+// one wins.
+func (p *Provider) OverrideForContext(t testing.TB, flagKey string, match openfeature.FlattenedContext, value interface{}) {
+	t.Helper()
+
+	o := &contextOverride{flagKey: flagKey, match: match, value: value}
+	p.mu.Lock()
+	p.overrides = append(p.overrides, o)
+	p.mu.Unlock()
+
+	t.Cleanup(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		for i, cur := range p.overrides {
+			if cur == o {
+				p.overrides = append(p.overrides[:i], p.overrides[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// Evaluations returns the evaluations served so far, oldest first.
+func (p *Provider) Evaluations() []Evaluation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Evaluation(nil), p.evaluations...)
+}
+
+// Reset forgets the evaluations served so far.
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.evaluations = nil
+}
+
+// AssertEvaluated fails the test unless every flag in flagKeys has been evaluated.
+func (p *Provider) AssertEvaluated(t testing.TB, flagKeys ...string) {
+	t.Helper()
+
+	seen := p.evaluatedKeys()
+	for _, key := range flagKeys {
+		if !seen[key] {
+			t.Errorf("flag %s was not evaluated", key)
+		}
+	}
+}
+
+// AssertNotEvaluated fails the test if any flag in flagKeys has been evaluated.
+func (p *Provider) AssertNotEvaluated(t testing.TB, flagKeys ...string) {
+	t.Helper()
+
+	seen := p.evaluatedKeys()
+	for _, key := range flagKeys {
+		if seen[key] {
+			t.Errorf("flag %s was evaluated", key)
+		}
+	}
+}
+
+func (p *Provider) evaluatedKeys() map[string]bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seen := make(map[string]bool, len(p.evaluations))
+	for _, e := range p.evaluations {
+		seen[e.FlagKey] = true
+	}
+	return seen
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: "providertest",
+	}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+// resolve looks flagKey up and records the evaluation. found is false when the
+// flag is not set at all.
+func (p *Provider) resolve(flagKey string, typ openfeature.Type, evalCtx openfeature.FlattenedContext) (val interface{}, reason openfeature.Reason, found bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	val, reason, found = p.lookup(flagKey, evalCtx)
+	p.evaluations = append(p.evaluations, Evaluation{
+		FlagKey: flagKey,
+		Type:    typ,
+		Context: evalCtx,
+		Value:   val,
+		Reason:  reason,
+	})
+	return val, reason, found
+}
+
+func (p *Provider) lookup(flagKey string, evalCtx openfeature.FlattenedContext) (interface{}, openfeature.Reason, bool) {
+	for i := len(p.overrides) - 1; i >= 0; i-- {
+		o := p.overrides[i]
+		if o.flagKey == flagKey && matches(o.match, evalCtx) {
+			return o.value, openfeature.TargetingMatchReason, true
+		}
+	}
+	if val, ok := p.flags[flagKey]; ok {
+		return val, openfeature.StaticReason, true
+	}
+	return nil, openfeature.ErrorReason, false
+}
+
+func matches(match, evalCtx openfeature.FlattenedContext) bool {
+	for k, want := range match {
+		got, ok := evalCtx[k]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func notFound(flagKey string) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %s is not set", flagKey)),
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
+func typeMismatch(flagKey string, val interface{}) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("flag %s is set to %T", flagKey, val)),
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flagKey string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	val, reason, found := p.resolve(flagKey, openfeature.Boolean, evalCtx)
+	if !found {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notFound(flagKey)}
+	}
+
+	boolVal, ok := val.(bool)
+	if !ok {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flagKey, val)}
+	}
+	return openfeature.BoolResolutionDetail{
+		Value:                    boolVal,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: reason},
+	}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flagKey string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	val, reason, found := p.resolve(flagKey, openfeature.String, evalCtx)
+	if !found {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notFound(flagKey)}
+	}
+
+	strVal, ok := val.(string)
+	if !ok {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flagKey, val)}
+	}
+	return openfeature.StringResolutionDetail{
+		Value:                    strVal,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: reason},
+	}
+}
+
+func (p *Provider) IntEvaluation(ctx context.Context, flagKey string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	val, reason, found := p.resolve(flagKey, openfeature.Int, evalCtx)
+	if !found {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notFound(flagKey)}
+	}
+
+	var intVal int64
+	switch v := val.(type) {
+	case int:
+		intVal = int64(v)
+	case int32:
+		intVal = int64(v)
+	case int64:
+		intVal = v
+	default:
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flagKey, val)}
+	}
+	return openfeature.IntResolutionDetail{
+		Value:                    intVal,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: reason},
+	}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flagKey string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	val, reason, found := p.resolve(flagKey, openfeature.Float, evalCtx)
+	if !found {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notFound(flagKey)}
+	}
+
+	var floatVal float64
+	switch v := val.(type) {
+	case float64:
+		floatVal = v
+	case float32:
+		floatVal = float64(v)
+	case int:
+		floatVal = float64(v)
+	case int64:
+		floatVal = float64(v)
+	default:
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flagKey, val)}
+	}
+	return openfeature.FloatResolutionDetail{
+		Value:                    floatVal,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: reason},
+	}
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flagKey string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	val, reason, found := p.resolve(flagKey, openfeature.Object, evalCtx)
+	if !found {
+		return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: notFound(flagKey)}
+	}
+	return openfeature.InterfaceResolutionDetail{
+		Value:                    val,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: reason},
+	}
+}
@@ -0,0 +1,173 @@
+package providertest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestProvider(t *testing.T) {
+	for name, test := range map[string]struct {
+		set     map[string]interface{}
+		flagKey string
+		want    openfeature.IntResolutionDetail
+	}{
+		"set": {
+			set:     map[string]interface{}{"limit": 10},
+			flagKey: "limit",
+			want: openfeature.IntResolutionDetail{
+				Value:                    10,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: openfeature.StaticReason},
+			},
+		},
+		"not set": {
+			flagKey: "limit",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag limit is not set"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+		"wrong type": {
+			set:     map[string]interface{}{"limit": "ten"},
+			flagKey: "limit",
+			want: openfeature.IntResolutionDetail{
+				Value: -1,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewTypeMismatchResolutionError("flag limit is set to string"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := New()
+			for k, v := range test.set {
+				p.Set(k, v)
+			}
+
+			got := p.IntEvaluation(context.Background(), test.flagKey, -1, nil)
+			opts := cmp.Comparer(func(a, b openfeature.ResolutionError) bool { return a.Error() == b.Error() })
+			if diff := cmp.Diff(test.want, got, opts); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOverride(t *testing.T) {
+	p := New()
+	p.Set("color", "red")
+
+	t.Run("override", func(t *testing.T) {
+		p.Override(t, "color", "blue")
+		p.Override(t, "size", "large")
+
+		if got := p.StringEvaluation(context.Background(), "color", "", nil).Value; got != "blue" {
+			t.Errorf("color = %q during the test, want blue", got)
+		}
+	})
+
+	if got := p.StringEvaluation(context.Background(), "color", "", nil).Value; got != "red" {
+		t.Errorf("color = %q after the test, want red", got)
+	}
+	if got := p.StringEvaluation(context.Background(), "size", "", nil); got.Reason != openfeature.ErrorReason {
+		t.Errorf("size = %+v after the test, want it unset", got)
+	}
+}
+
+func TestOverrideForContext(t *testing.T) {
+	p := New()
+	p.Set("beta", false)
+
+	user1 := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1", "plan": "pro"}
+	user2 := openfeature.FlattenedContext{openfeature.TargetingKey: "user-2", "plan": "pro"}
+
+	t.Run("override", func(t *testing.T) {
+		p.OverrideForContext(t, "beta", openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}, true)
+
+		got := p.BooleanEvaluation(context.Background(), "beta", false, user1)
+		want := openfeature.BoolResolutionDetail{
+			Value:                    true,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: openfeature.TargetingMatchReason},
+		}
+		if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(openfeature.ResolutionError{})); diff != "" {
+			t.Errorf("user-1 result mismatch (-want +got):\n%s", diff)
+		}
+		if p.BooleanEvaluation(context.Background(), "beta", false, user2).Value {
+			t.Error("user-2 got the user-1 override")
+		}
+	})
+
+	if p.BooleanEvaluation(context.Background(), "beta", false, user1).Value {
+		t.Error("user-1 override outlived the test")
+	}
+}
+
+func TestEvaluations(t *testing.T) {
+	t.Parallel()
+
+	p := New()
+	p.Set("new_checkout", true)
+	p.Set("discount", 0.1)
+	client := NewClient(t, p)
+
+	evalCtx := openfeature.NewTargetlessEvaluationContext(map[string]interface{}{"country": "JP"})
+	if !client.Boolean(context.Background(), "new_checkout", false, evalCtx) {
+		t.Error("new_checkout = false, want true")
+	}
+	if got := client.Float(context.Background(), "discount", 0, evalCtx); got != 0.1 {
+		t.Errorf("discount = %v, want 0.1", got)
+	}
+
+	p.AssertEvaluated(t, "new_checkout", "discount")
+	p.AssertNotEvaluated(t, "legacy_checkout")
+
+	want := []Evaluation{
+		{FlagKey: "new_checkout", Type: openfeature.Boolean, Context: openfeature.FlattenedContext{"country": "JP"}, Value: true, Reason: openfeature.StaticReason},
+		{FlagKey: "discount", Type: openfeature.Float, Context: openfeature.FlattenedContext{"country": "JP"}, Value: 0.1, Reason: openfeature.StaticReason},
+	}
+	if diff := cmp.Diff(want, p.Evaluations()); diff != "" {
+		t.Errorf("evaluations mismatch (-want +got):\n%s", diff)
+	}
+
+	p.Reset()
+	if got := p.Evaluations(); len(got) != 0 {
+		t.Errorf("Evaluations() after Reset = %v, want none", got)
+	}
+}
+
+func TestAssertionsFail(t *testing.T) {
+	p := New()
+	p.Set("flag", true)
+	p.BooleanEvaluation(context.Background(), "flag", false, nil)
+
+	spy := &recordingT{TB: t}
+	p.AssertEvaluated(spy, "other")
+	p.AssertNotEvaluated(spy, "flag")
+
+	want := []string{"flag other was not evaluated", "flag flag was evaluated"}
+	if diff := cmp.Diff(want, spy.errors); diff != "" {
+		t.Errorf("assertion failures mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}